@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// accessBannedTypes are the kinds of entities that can be banned, always exported.
+var accessBannedTypes = []string{"user", "group", "host", "domain"}
+
+// accessRuleTypes are the kinds of rules, always exported.
+var accessRuleTypes = []string{"stall", "redirect", "limit"}
+
+type AccessCollector struct {
+	opt *eosclient.Options
+
+	Banned       *prometheus.Desc
+	Rules        *prometheus.Desc
+	StallSeconds *prometheus.Desc
+}
+
+// NewAccessCollector creates an instance of the AccessCollector
+func NewAccessCollector(cluster string, opt *eosclient.Options) *AccessCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &AccessCollector{
+		opt: opt,
+
+		Banned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "access_banned"),
+			"Access number of banned users, groups, hosts or domains",
+			[]string{"type"},
+			labels,
+		),
+		Rules: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "access_rules"),
+			"Access number of stall, redirection or limit rules",
+			[]string{"type"},
+			labels,
+		),
+		StallSeconds: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "access_stall_seconds"),
+			"Access stall time of the clients matching the rule",
+			[]string{"rule"},
+			labels,
+		),
+	}
+}
+
+func (o *AccessCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Banned,
+		o.Rules,
+		o.StallSeconds,
+	}
+}
+
+func (o *AccessCollector) collectAccessDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.ListAccess(ctx)
+	if err != nil {
+		return err
+	}
+
+	banned := make(map[string]float64)
+	for _, t := range accessBannedTypes {
+		banned[t] = 0
+	}
+	rules := make(map[string]float64)
+	for _, t := range accessRuleTypes {
+		rules[t] = 0
+	}
+
+	for _, m := range mds {
+		x := strings.SplitN(m.Key, ".", 2)
+		if len(x) != 2 {
+			continue
+		}
+		switch {
+		case x[1] == "banned":
+			// e.g. user.banned=1000
+			if _, ok := banned[x[0]]; ok {
+				banned[x[0]]++
+			}
+		case x[0] == "redirect":
+			// e.g. redirect.r:*=eos-backup.cern.ch:1094
+			rules["redirect"]++
+		case x[0] == "stall" && (strings.HasPrefix(x[1], "rate:") || strings.HasPrefix(x[1], "threads:")):
+			// the limits are stall rules, e.g. stall.threads:*=500
+			rules["limit"]++
+		case x[0] == "stall":
+			// e.g. stall.w:*=60
+			rules["stall"]++
+			stall, err := strconv.ParseFloat(m.Value, 64)
+			if err == nil {
+				ch <- prometheus.MustNewConstMetric(o.StallSeconds, prometheus.GaugeValue, stall, x[1])
+			}
+		}
+	}
+
+	for t, count := range banned {
+		ch <- prometheus.MustNewConstMetric(o.Banned, prometheus.GaugeValue, count, t)
+	}
+
+	for t, count := range rules {
+		ch <- prometheus.MustNewConstMetric(o.Rules, prometheus.GaugeValue, count, t)
+	}
+
+	return nil
+
+} // collectAccessDF()
+
+// Describe sends the descriptors of each AccessCollector related metrics we have defined
+func (o *AccessCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *AccessCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectAccessDF(ctx, ch)
+}
@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestAccessCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"access ls -m": {Stdout: "user.banned=1000\nuser.banned=1001\nhost.banned=bad.cern.ch\nuser.allowed=0\nstall.*=60\nstall.threads:*=500\nstall.rate:user:*:OpenRead=100\nredirect.w:*=eos-backup.cern.ch:1094\n"},
+	})
+
+	got := collect(t, NewAccessCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_access_banned{cluster="test",type="user"}`:     2,
+		`eos_access_banned{cluster="test",type="host"}`:     1,
+		`eos_access_banned{cluster="test",type="group"}`:    0,
+		`eos_access_banned{cluster="test",type="domain"}`:   0,
+		`eos_access_rules{cluster="test",type="stall"}`:     1,
+		`eos_access_rules{cluster="test",type="limit"}`:     2,
+		`eos_access_rules{cluster="test",type="redirect"}`:  1,
+		`eos_access_stall_seconds{cluster="test",rule="*"}`: 60,
+	})
+	// the limits are not stall times
+	checkAbsent(t, got, `eos_access_stall_seconds{cluster="test",rule="threads`, `eos_access_stall_seconds{cluster="test",rule="rate`)
+}
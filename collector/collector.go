@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// Collector is the interface implemented by every EOS collector.
+type Collector interface {
+	// Describe sends the descriptors of the metrics exported by the collector.
+	Describe(ch chan<- *prometheus.Desc)
+
+	// Update lists the EOS entities and sends the resulting metrics to ch.
+	// An error means the listing failed and no metrics were sent.
+	// The listing is abandoned once ctx is done.
+	Update(ctx context.Context, ch chan<- prometheus.Metric) error
+}
+
+// BackgroundCollector is implemented by the collectors whose listings run on their own schedule,
+// independently of the scrapes. Update sends the outcome of the last run.
+type BackgroundCollector interface {
+	Collector
+
+	// Start runs the listings in the background until ctx is done.
+	Start(ctx context.Context)
+}
+
+// newEOSClient returns a client configured with the exporter-wide options.
+// The options are copied, as the client fills in their defaults.
+func newEOSClient(opt *eosclient.Options) (*eosclient.Client, error) {
+	o := *opt
+	return eosclient.New(&o)
+}
+
+type sharedListingsKey struct{}
+
+// sharedListings holds the listings run so far within a context, by name.
+type sharedListings struct {
+	mu    sync.Mutex
+	calls map[string]*sharedListing
+}
+
+// sharedListing is a listing run once and used by several collectors.
+type sharedListing struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// WithSharedListings returns a context in which the collectors updated with it
+// run the listings they have in common only once, e.g. the ns and ns_activity
+// collectors share "eos ns stat". It is meant to span a single scrape or poll.
+func WithSharedListings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedListingsKey{}, &sharedListings{calls: make(map[string]*sharedListing)})
+}
+
+// shareListing runs list, unless the listing named name already ran or is running
+// in the context, in which case its outcome is returned.
+func shareListing(ctx context.Context, name string, list func() (interface{}, error)) (interface{}, error) {
+	s, ok := ctx.Value(sharedListingsKey{}).(*sharedListings)
+	if !ok {
+		return list()
+	}
+
+	s.mu.Lock()
+	call, running := s.calls[name]
+	if !running {
+		call = &sharedListing{done: make(chan struct{})}
+		s.calls[name] = call
+	}
+	s.mu.Unlock()
+
+	if !running {
+		call.value, call.err = list()
+		close(call.done)
+		return call.value, call.err
+	}
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
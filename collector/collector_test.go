@@ -0,0 +1,170 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// fakeOptions returns the client options of a collector running the eos commands against the canned responses.
+func fakeOptions(responses map[string]eosclient.FakeResponse) *eosclient.Options {
+	return &eosclient.Options{
+		URL:       "root://eos-test.example.org",
+		RoleUser:  "0",
+		RoleGroup: "0",
+		Runner:    eosclient.NewFakeRunner(responses),
+	}
+}
+
+// updater registers a Collector the way the exporter runs it in a scrape.
+type updater struct {
+	c   Collector
+	err error
+}
+
+func (u *updater) Describe(ch chan<- *prometheus.Desc) {
+	u.c.Describe(ch)
+}
+
+func (u *updater) Collect(ch chan<- prometheus.Metric) {
+	u.err = u.c.Update(context.Background(), ch)
+}
+
+// collect updates the collector and returns the value of every series sent, keyed by name{labels},
+// with the labels sorted by name. The metrics are checked against the descriptors by a pedantic registry.
+func collect(t *testing.T, c Collector) map[string]float64 {
+	t.Helper()
+	u := &updater{c: c}
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(u); err != nil {
+		t.Fatal(err)
+	}
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.err != nil {
+		t.Fatalf("Update() failed: %s", u.err)
+	}
+
+	series := make(map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+"=\""+l.GetValue()+"\"")
+			}
+			sort.Strings(labels)
+			series[mf.GetName()+"{"+strings.Join(labels, ",")+"}"] = m.GetGauge().GetValue() + m.GetCounter().GetValue() + m.GetUntyped().GetValue()
+		}
+	}
+	return series
+}
+
+// checkSeries reports the series missing from got or with another value than in want.
+func checkSeries(t *testing.T, got, want map[string]float64) {
+	t.Helper()
+	for name, v := range want {
+		if gv, ok := got[name]; !ok {
+			t.Errorf("missing %s", name)
+		} else if gv != v {
+			t.Errorf("%s = %v, want %v", name, gv, v)
+		}
+	}
+}
+
+// containsLabel tells whether the series, as returned by collect, has the label with the value.
+func containsLabel(series, label, value string) bool {
+	return strings.Contains(series, label+"=\""+value+"\"")
+}
+
+// checkAbsent reports the series of got whose name starts with one of the prefixes.
+func checkAbsent(t *testing.T, got map[string]float64, prefixes ...string) {
+	t.Helper()
+	for name := range got {
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p) {
+				t.Errorf("unexpected %s", name)
+			}
+		}
+	}
+}
+
+func TestUpdateFailure(t *testing.T) {
+	failed := eosclient.FakeResponse{Stderr: "error: connection refused", Status: 1}
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"ls -m":          failed,
+		"version":        failed,
+		"--json node ls": failed,
+		"ns stat -a -m":  failed,
+	})
+
+	collectors := map[string]Collector{
+		"space":       NewSpaceCollector("test", opt),
+		"group":       NewGroupCollector("test", opt),
+		"node":        NewNodeCollector("test", opt),
+		"fs":          NewFSCollector("test", opt),
+		"vs":          NewVSCollector("test", opt),
+		"ns":          NewNSCollector("test", opt),
+		"ns_activity": NewNSActivityCollector("test", opt, 0),
+	}
+	for name, c := range collectors {
+		ch := make(chan prometheus.Metric, 1000)
+		if err := c.Update(context.Background(), ch); err == nil {
+			t.Errorf("%s: Update() succeeded with a failing eos command", name)
+		}
+		if len(ch) != 0 {
+			t.Errorf("%s: Update() sent %d metrics with a failing eos command", name, len(ch))
+		}
+	}
+}
+
+func TestShareListing(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	list := func() (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return calls, nil
+	}
+
+	v, _ := shareListing(context.Background(), "a", list)
+	w, _ := shareListing(context.Background(), "a", list)
+	if v != 1 || w != 2 {
+		t.Errorf("without shared listings, got %v and %v, want every call to list", v, w)
+	}
+
+	calls = 0
+	ctx := WithSharedListings(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := shareListing(ctx, "a", list); v != 1 || err != nil {
+				t.Errorf("shareListing(a) = %v, %v, want the first listing", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := shareListing(ctx, "b", list); v != 2 {
+		t.Errorf("shareListing(b) = %v, want another listing", v)
+	}
+
+	failing := errors.New("failed")
+	ctx = WithSharedListings(context.Background())
+	for i := 0; i < 2; i++ {
+		if _, err := shareListing(ctx, "a", func() (interface{}, error) { return nil, failing }); err != failing {
+			t.Errorf("shareListing() error = %v, want %v", err, failing)
+		}
+	}
+}
@@ -1,11 +1,7 @@
 package collector
 
 import (
-	"bufio"
 	"context"
-	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
@@ -14,317 +10,255 @@ import (
 )
 
 type FSCollector struct {
-	Host                       *prometheus.GaugeVec
-	Port                       *prometheus.GaugeVec
-	Id                         *prometheus.GaugeVec
-	Uuid                       *prometheus.GaugeVec
-	Path                       *prometheus.GaugeVec
-	Schedgroup                 *prometheus.GaugeVec
-	StatBoot                   *prometheus.GaugeVec
-	Configstatus               *prometheus.GaugeVec
-	Headroom                   *prometheus.GaugeVec
-	StatErrc                   *prometheus.GaugeVec
-	StatErrmsg                 *prometheus.GaugeVec
-	StatDiskLoad               *prometheus.GaugeVec
-	StatDiskReadratemb         *prometheus.GaugeVec
-	StatDiskWriteratemb        *prometheus.GaugeVec
-	StatNetEthratemib          *prometheus.GaugeVec
-	StatNetInratemib           *prometheus.GaugeVec
-	StatNetOutratemib          *prometheus.GaugeVec
-	StatRopen                  *prometheus.GaugeVec
-	StatWopen                  *prometheus.GaugeVec
-	StatStatfsFreebytes        *prometheus.GaugeVec
-	StatStatfsUsedbytes        *prometheus.GaugeVec
-	StatStatfsCapacity         *prometheus.GaugeVec
-	StatUsedfiles              *prometheus.GaugeVec
-	StatStatfsFfree            *prometheus.GaugeVec
-	StatStatfsFused            *prometheus.GaugeVec
-	StatStatfsFiles            *prometheus.GaugeVec
-	Drainstatus                *prometheus.GaugeVec
-	StatDrainprogress          *prometheus.GaugeVec
-	StatDrainfiles             *prometheus.GaugeVec
-	StatDrainbytesleft         *prometheus.GaugeVec
-	StatDrainretry             *prometheus.GaugeVec
-	StatDrainFailed            *prometheus.GaugeVec
-	Graceperiod                *prometheus.GaugeVec
-	StatTimeleft               *prometheus.GaugeVec
-	StatActive                 *prometheus.GaugeVec
-	StatBalancerRunning        *prometheus.GaugeVec
-	StatDrainerRunning         *prometheus.GaugeVec
-	StatDiskIops               *prometheus.GaugeVec
-	StatDiskBw                 *prometheus.GaugeVec
-	StatGeotag                 *prometheus.GaugeVec
-	StatHealth                 *prometheus.GaugeVec
-	StatHealthRedundancyFactor *prometheus.GaugeVec
-	StatHealthDrivesFailed     *prometheus.GaugeVec
-	StatHealthDrivesTotal      *prometheus.GaugeVec
-	StatHealthIndicator        *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	Host                       *prometheus.Desc
+	Port                       *prometheus.Desc
+	Id                         *prometheus.Desc
+	Uuid                       *prometheus.Desc
+	Path                       *prometheus.Desc
+	Schedgroup                 *prometheus.Desc
+	StatBoot                   *prometheus.Desc
+	Configstatus               *prometheus.Desc
+	Headroom                   *prometheus.Desc
+	StatErrc                   *prometheus.Desc
+	StatErrmsg                 *prometheus.Desc
+	StatDiskLoad               *prometheus.Desc
+	StatDiskReadratemb         *prometheus.Desc
+	StatDiskWriteratemb        *prometheus.Desc
+	StatNetEthratemib          *prometheus.Desc
+	StatNetInratemib           *prometheus.Desc
+	StatNetOutratemib          *prometheus.Desc
+	StatRopen                  *prometheus.Desc
+	StatWopen                  *prometheus.Desc
+	StatStatfsFreebytes        *prometheus.Desc
+	StatStatfsUsedbytes        *prometheus.Desc
+	StatStatfsCapacity         *prometheus.Desc
+	StatUsedfiles              *prometheus.Desc
+	StatStatfsFfree            *prometheus.Desc
+	StatStatfsFused            *prometheus.Desc
+	StatStatfsFiles            *prometheus.Desc
+	Drainstatus                *prometheus.Desc
+	StatDrainprogress          *prometheus.Desc
+	StatDrainfiles             *prometheus.Desc
+	StatDrainbytesleft         *prometheus.Desc
+	StatDrainretry             *prometheus.Desc
+	StatDrainFailed            *prometheus.Desc
+	Graceperiod                *prometheus.Desc
+	StatTimeleft               *prometheus.Desc
+	StatActive                 *prometheus.Desc
+	StatBalancerRunning        *prometheus.Desc
+	StatDrainerRunning         *prometheus.Desc
+	StatDiskIops               *prometheus.Desc
+	StatDiskBw                 *prometheus.Desc
+	StatGeotag                 *prometheus.Desc
+	StatHealth                 *prometheus.Desc
+	StatHealthRedundancyFactor *prometheus.Desc
+	StatHealthDrivesFailed     *prometheus.Desc
+	StatHealthDrivesTotal      *prometheus.Desc
+	StatHealthIndicator        *prometheus.Desc
+	Info                       *prometheus.Desc
 }
 
 //NewFSCollector creates an cluster of the FSCollector and instantiates
 // the individual metrics that show information about the FS.
-func NewFSCollector(cluster string) *FSCollector {
+func NewFSCollector(cluster string, opt *eosclient.Options) *FSCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &FSCollector{
-		StatBoot: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_boot_status",
-				Help:        "FS Status 0=booted, 1=booting, 2=bootfailure, 3=opserror, 4=down",
-				ConstLabels: labels,
-			},
+		opt: opt,
+		StatBoot: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_boot_status"),
+			"FS Status 0=booted, 1=booting, 2=bootfailure, 3=opserror, 4=down",
 			[]string{"fs", "node"},
+			labels,
 		),
-		Configstatus: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_config_status",
-				Help:        "Configstatus: 0=rw,1=ro,2=drain,3=empty",
-				ConstLabels: labels,
-			},
+		Configstatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_config_status"),
+			"Configstatus: 0=rw,1=ro,2=drain,3=empty",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDiskLoad: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_load",
-				Help:        "FS disk load",
-				ConstLabels: labels,
-			},
+		StatDiskLoad: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_load"),
+			"FS disk load",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDiskReadratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_readratemb",
-				Help:        "FS stat Disk Read Rate in MB/s",
-				ConstLabels: labels,
-			},
+		StatDiskReadratemb: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_readratemb"),
+			"FS stat Disk Read Rate in MB/s",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDiskWriteratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_writeratemb",
-				Help:        "FS Stat Disk Write Rate in MB/s",
-				ConstLabels: labels,
-			},
+		StatDiskWriteratemb: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_writeratemb"),
+			"FS Stat Disk Write Rate in MB/s",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatNetEthratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_net_ethratemib",
-				Help:        "FS Stat Net Eth Rate in MiB/s",
-				ConstLabels: labels,
-			},
+		StatNetEthratemib: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_net_ethratemib"),
+			"FS Stat Net Eth Rate in MiB/s",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatNetInratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_net_inratemib",
-				Help:        "FS Stat Net In Rate MiB/s",
-				ConstLabels: labels,
-			},
+		StatNetInratemib: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_net_inratemib"),
+			"FS Stat Net In Rate MiB/s",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatNetOutratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_net_outratemib",
-				Help:        "FS Stat Net Out Rate MiB/s",
-				ConstLabels: labels,
-			},
+		StatNetOutratemib: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_net_outratemib"),
+			"FS Stat Net Out Rate MiB/s",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatRopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_ropen",
-				Help:        "FS Open reads",
-				ConstLabels: labels,
-			},
+		StatRopen: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_ropen"),
+			"FS Open reads",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatWopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_wopen",
-				Help:        "FS Open writes",
-				ConstLabels: labels,
-			},
+		StatWopen: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_wopen"),
+			"FS Open writes",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsUsedbytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_usedbytes",
-				Help:        "FS StatFs Used Bytes",
-				ConstLabels: labels,
-			},
+		StatStatfsUsedbytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_usedbytes"),
+			"FS StatFs Used Bytes",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsFreebytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_freebytes",
-				Help:        "FS StatFs Free Bytes",
-				ConstLabels: labels,
-			},
+		StatStatfsFreebytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_freebytes"),
+			"FS StatFs Free Bytes",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsCapacity: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_sizebytes",
-				Help:        "FS StatFs Capacity",
-				ConstLabels: labels,
-			},
+		StatStatfsCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_sizebytes"),
+			"FS StatFs Capacity",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsFused: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_usedfiles",
-				Help:        "FS Used Files",
-				ConstLabels: labels,
-			},
+		StatStatfsFused: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_usedfiles"),
+			"FS Used Files",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsFfree: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_freefiles",
-				Help:        "FS Free-Files",
-				ConstLabels: labels,
-			},
+		StatStatfsFfree: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_freefiles"),
+			"FS Free-Files",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatStatfsFiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_statfs_totalfiles",
-				Help:        "FS Files",
-				ConstLabels: labels,
-			},
+		StatStatfsFiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_statfs_totalfiles"),
+			"FS Files",
 			[]string{"fs", "node"},
+			labels,
 		),
-		Drainstatus: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_status",
-				Help:        "FS Drain status: 0=nodrain,1=drained,2=draining,3=stalling,4=expired",
-				ConstLabels: labels,
-			},
+		Drainstatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_status"),
+			"FS Drain status: 0=nodrain,1=drained,2=draining,3=stalling,4=expired",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainprogress: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_progress",
-				Help:        "FS Drain progress %",
-				ConstLabels: labels,
-			},
+		StatDrainprogress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_progress"),
+			"FS Drain progress %",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainfiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_filesleft",
-				Help:        "FS Drain files left",
-				ConstLabels: labels,
-			},
+		StatDrainfiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_filesleft"),
+			"FS Drain files left",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainbytesleft: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_bytesleft",
-				Help:        "FS Drain bytes left",
-				ConstLabels: labels,
-			},
+		StatDrainbytesleft: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_bytesleft"),
+			"FS Drain bytes left",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainretry: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_retries",
-				Help:        "FS Drain retries",
-				ConstLabels: labels,
-			},
+		StatDrainretry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_retries"),
+			"FS Drain retries",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainFailed: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_failed",
-				Help:        "FS Drain failed",
-				ConstLabels: labels,
-			},
+		StatDrainFailed: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_failed"),
+			"FS Drain failed",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatActive: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_status",
-				Help:        "Status of fs: 0=offline,1=online",
-				ConstLabels: labels,
-			},
+		StatActive: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_status"),
+			"Status of fs: 0=offline,1=online",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatBalancerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_balancer_running",
-				Help:        "FS Stat Balancer Running",
-				ConstLabels: labels,
-			},
+		StatBalancerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_balancer_running"),
+			"FS Stat Balancer Running",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDrainerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_drain_running",
-				Help:        "FS Stat Drainer Running",
-				ConstLabels: labels,
-			},
+		StatDrainerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_drain_running"),
+			"FS Stat Drainer Running",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDiskIops: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_iops",
-				Help:        "FS Stat Disk IOPS",
-				ConstLabels: labels,
-			},
+		StatDiskIops: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_iops"),
+			"FS Stat Disk IOPS",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatDiskBw: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_disk_bw_MB",
-				Help:        "FS Stat Disk BW MB/Sec",
-				ConstLabels: labels,
-			},
+		StatDiskBw: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_disk_bw_MB"),
+			"FS Stat Disk BW MB/Sec",
 			[]string{"fs", "node"},
+			labels,
 		),
-		StatHealth: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "fs_health",
-				Help:        "FS Stat Health: 0=OK,1=other",
-				ConstLabels: labels,
-			},
+		StatErrc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_error_code"),
+			"FS Stat Error code (errno), 0 if no error",
 			[]string{"fs", "node"},
+			labels,
+		),
+		StatErrmsg: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_error_info"),
+			"FS Stat Error message class, e.g. \"no such device\", \"read-only filesystem\" or \"timeout\", always 1. Only exported for the filesystems in error",
+			[]string{"fs", "node", "class"},
+			labels,
+		),
+		StatHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_health"),
+			"FS Stat Health: 0=OK,1=other",
+			[]string{"fs", "node"},
+			labels,
+		),
+		Info: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fs_info"),
+			"FS Location of the filesystem: node, port, path, uuid, scheduling group, space and geotag, always 1",
+			[]string{"fs", "node", "port", "path", "uuid", "schedgroup", "space", "geotag"},
+			labels,
 		),
 	}
 }
 
-func (o *FSCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *FSCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.StatBoot,
 		o.Configstatus,
 		o.StatDiskLoad,
@@ -353,47 +287,71 @@ func (o *FSCollector) collectorList() []prometheus.Collector {
 		o.StatDiskIops,
 		o.StatDiskBw,
 		o.StatHealth,
+		o.StatErrc,
+		o.StatErrmsg,
+		o.Info,
 	}
 }
 
-func getEOSInstance() string {
-	// Get the EOS cluster name from MGM's filesystem
-	var str string
+// fsErrorClasses normalize the error messages of the filesystems, by the first fragment found in the message.
+var fsErrorClasses = []struct {
+	fragment string
+	class    string
+}{
+	{"no such device", "no such device"},
+	{"no such file", "no such file"},
+	{"read-only file", "read-only filesystem"},
+	{"timed out", "timeout"},
+	{"timeout", "timeout"},
+	{"input/output", "input/output error"},
+	{"no space left", "no space left"},
+	{"permission denied", "permission denied"},
+	{"not permitted", "permission denied"},
+	{"connection refused", "connection refused"},
+}
 
-	file, err := os.Open("/etc/sysconfig/eos_env")
-	if err != nil {
-		fmt.Println(err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		l := scanner.Text()
-		if strings.HasPrefix(l, "EOS_INSTANCE_NAME=") {
-			s := strings.Split(l, "EOS_INSTANCE_NAME=")
-			str = strings.Replace(s[1], "\"", "", -1)
+// fsErrnoClasses are the classes of the error codes, for the filesystems without message.
+var fsErrnoClasses = map[string]string{
+	"1":   "permission denied",
+	"2":   "no such file",
+	"5":   "input/output error",
+	"13":  "permission denied",
+	"19":  "no such device",
+	"28":  "no space left",
+	"30":  "read-only filesystem",
+	"110": "timeout",
+	"111": "connection refused",
+}
+
+// fsErrorClass returns the class of the error of a filesystem, "other" if unknown.
+func fsErrorClass(errc, errmsg string) string {
+	msg := strings.ToLower(errmsg)
+	for _, c := range fsErrorClasses {
+		if strings.Contains(msg, c.fragment) {
+			return c.class
 		}
 	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
+	if class, ok := fsErrnoClasses[errc]; ok && strings.TrimSpace(msg) == "" {
+		return class
 	}
+	return "other"
+}
 
-	return str
+// schedgroupSpace returns the space of a scheduling group, e.g. default for default.12.
+// Groups without index, like spare, are their own space.
+func schedgroupSpace(schedgroup string) string {
+	return strings.SplitN(schedgroup, ".", 2)[0]
 }
 
-func (o *FSCollector) collectFSDF() error {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
+func (o *FSCollector) collectFSDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	mds, err := client.ListFS(context.Background(), "root")
+	mds, err := client.ListFS(ctx)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	for _, m := range mds {
@@ -416,7 +374,7 @@ func (o *FSCollector) collectFSDF() error {
 			boot_status = 4
 		}
 
-		o.StatBoot.WithLabelValues(m.Id, m.Host).Set(float64(boot_status))
+		ch <- prometheus.MustNewConstMetric(o.StatBoot, prometheus.GaugeValue, float64(boot_status), m.Id, m.Host)
 
 		// Config Status
 
@@ -434,76 +392,76 @@ func (o *FSCollector) collectFSDF() error {
 			config_status = 0
 		}
 
-		o.Configstatus.WithLabelValues(m.Id, m.Host).Set(float64(config_status))
+		ch <- prometheus.MustNewConstMetric(o.Configstatus, prometheus.GaugeValue, float64(config_status), m.Id, m.Host)
 
 		diskload, err := strconv.ParseFloat(m.StatDiskLoad, 64)
 		if err == nil {
-			o.StatDiskLoad.WithLabelValues(m.Id, m.Host).Set(diskload)
+			ch <- prometheus.MustNewConstMetric(o.StatDiskLoad, prometheus.GaugeValue, diskload, m.Id, m.Host)
 		}
 
 		diskr, err := strconv.ParseFloat(m.StatDiskReadratemb, 64)
 		if err == nil {
-			o.StatDiskReadratemb.WithLabelValues(m.Id, m.Host).Set(diskr)
+			ch <- prometheus.MustNewConstMetric(o.StatDiskReadratemb, prometheus.GaugeValue, diskr, m.Id, m.Host)
 		}
 
 		diskw, err := strconv.ParseFloat(m.StatDiskWriteratemb, 64)
 		if err == nil {
-			o.StatDiskWriteratemb.WithLabelValues(m.Id, m.Host).Set(diskw)
+			ch <- prometheus.MustNewConstMetric(o.StatDiskWriteratemb, prometheus.GaugeValue, diskw, m.Id, m.Host)
 		}
 
 		ethrate, err := strconv.ParseFloat(m.StatNetEthratemib, 64)
 		if err == nil {
-			o.StatNetEthratemib.WithLabelValues(m.Id, m.Host).Set(ethrate)
+			ch <- prometheus.MustNewConstMetric(o.StatNetEthratemib, prometheus.GaugeValue, ethrate, m.Id, m.Host)
 		}
 
 		inrate, err := strconv.ParseFloat(m.StatNetInratemib, 64)
 		if err == nil {
-			o.StatNetInratemib.WithLabelValues(m.Id, m.Host).Set(inrate)
+			ch <- prometheus.MustNewConstMetric(o.StatNetInratemib, prometheus.GaugeValue, inrate, m.Id, m.Host)
 		}
 
 		outrate, err := strconv.ParseFloat(m.StatNetOutratemib, 64)
 		if err == nil {
-			o.StatNetOutratemib.WithLabelValues(m.Id, m.Host).Set(outrate)
+			ch <- prometheus.MustNewConstMetric(o.StatNetOutratemib, prometheus.GaugeValue, outrate, m.Id, m.Host)
 		}
 
 		ropen, err := strconv.ParseFloat(m.StatRopen, 64)
 		if err == nil {
-			o.StatRopen.WithLabelValues(m.Id, m.Host).Set(ropen)
+			ch <- prometheus.MustNewConstMetric(o.StatRopen, prometheus.GaugeValue, ropen, m.Id, m.Host)
 		}
 
 		wopen, err := strconv.ParseFloat(m.StatWopen, 64)
 		if err == nil {
-			o.StatWopen.WithLabelValues(m.Id, m.Host).Set(wopen)
+			ch <- prometheus.MustNewConstMetric(o.StatWopen, prometheus.GaugeValue, wopen, m.Id, m.Host)
 		}
 
 		usedb, err := strconv.ParseFloat(m.StatStatfsUsedbytes, 64)
 		if err == nil {
-			o.StatStatfsUsedbytes.WithLabelValues(m.Id, m.Host).Set(usedb)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsUsedbytes, prometheus.GaugeValue, usedb, m.Id, m.Host)
 		}
 
 		fbytes, err := strconv.ParseFloat(m.StatStatfsFreebytes, 64)
 		if err == nil {
-			o.StatStatfsFreebytes.WithLabelValues(m.Id, m.Host).Set(fbytes)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsFreebytes, prometheus.GaugeValue, fbytes, m.Id, m.Host)
 		}
 
 		fscap, err := strconv.ParseFloat(m.StatStatfsCapacity, 64)
 		if err == nil {
-			o.StatStatfsCapacity.WithLabelValues(m.Id, m.Host).Set(fscap)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsCapacity, prometheus.GaugeValue, fscap, m.Id, m.Host)
 		}
 
 		ufiles, err := strconv.ParseFloat(m.StatStatfsFused, 64)
 		if err == nil {
-			o.StatStatfsFused.WithLabelValues(m.Id, m.Host).Set(ufiles)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsFused, prometheus.GaugeValue, ufiles, m.Id, m.Host)
 		}
 
 		ffree, err := strconv.ParseFloat(m.StatStatfsFfree, 64)
 		if err == nil {
-			o.StatStatfsFfree.WithLabelValues(m.Id, m.Host).Set(ffree)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsFfree, prometheus.GaugeValue, ffree, m.Id, m.Host)
 		}
 
 		files, err := strconv.ParseFloat(m.StatStatfsFiles, 64)
 		if err == nil {
-			o.StatStatfsFiles.WithLabelValues(m.Id, m.Host).Set(files)
+			ch <- prometheus.MustNewConstMetric(o.StatStatfsFiles, prometheus.GaugeValue, files, m.Id, m.Host)
 		}
 
 		// Drain Status.
@@ -524,36 +482,36 @@ func (o *FSCollector) collectFSDF() error {
 			drain_status = 0
 		}
 
-		o.Drainstatus.WithLabelValues(m.Id, m.Host).Set(float64(drain_status))
+		ch <- prometheus.MustNewConstMetric(o.Drainstatus, prometheus.GaugeValue, float64(drain_status), m.Id, m.Host)
 
 		balr, err := strconv.ParseFloat(m.StatBalancerRunning, 64)
 		if err == nil {
-			o.StatBalancerRunning.WithLabelValues(m.Id, m.Host).Set(balr)
+			ch <- prometheus.MustNewConstMetric(o.StatBalancerRunning, prometheus.GaugeValue, balr, m.Id, m.Host)
 		}
 
 		drainr, err := strconv.ParseFloat(m.StatDrainerRunning, 64)
 		if err == nil {
-			o.StatDrainerRunning.WithLabelValues(m.Id, m.Host).Set(drainr)
+			ch <- prometheus.MustNewConstMetric(o.StatDrainerRunning, prometheus.GaugeValue, drainr, m.Id, m.Host)
 		}
 
 		drainretry, err := strconv.ParseFloat(m.StatDrainretry, 64)
 		if err == nil {
-			o.StatDrainretry.WithLabelValues(m.Id, m.Host).Set(drainretry)
+			ch <- prometheus.MustNewConstMetric(o.StatDrainretry, prometheus.GaugeValue, drainretry, m.Id, m.Host)
 		}
 
 		drainfailed, err := strconv.ParseFloat(m.StatDrainFailed, 64)
 		if err == nil {
-			o.StatDrainFailed.WithLabelValues(m.Id, m.Host).Set(drainfailed)
+			ch <- prometheus.MustNewConstMetric(o.StatDrainFailed, prometheus.GaugeValue, drainfailed, m.Id, m.Host)
 		}
 
 		diskiops, err := strconv.ParseFloat(m.StatDiskIops, 64)
 		if err == nil {
-			o.StatDiskIops.WithLabelValues(m.Id, m.Host).Set(diskiops)
+			ch <- prometheus.MustNewConstMetric(o.StatDiskIops, prometheus.GaugeValue, diskiops, m.Id, m.Host)
 		}
 
 		diskbw, err := strconv.ParseFloat(m.StatDiskBw, 64)
 		if err == nil {
-			o.StatDiskBw.WithLabelValues(m.Id, m.Host).Set(diskbw)
+			ch <- prometheus.MustNewConstMetric(o.StatDiskBw, prometheus.GaugeValue, diskbw, m.Id, m.Host)
 		}
 
 		// FS Active Status.
@@ -568,7 +526,7 @@ func (o *FSCollector) collectFSDF() error {
 			active_status = 1
 		}
 
-		o.StatActive.WithLabelValues(m.Id, m.Host).Set(float64(active_status))
+		ch <- prometheus.MustNewConstMetric(o.StatActive, prometheus.GaugeValue, float64(active_status), m.Id, m.Host)
 
 		// Health
 
@@ -578,7 +536,21 @@ func (o *FSCollector) collectFSDF() error {
 		} else {
 			health = 1
 		}
-		o.StatHealth.WithLabelValues(m.Id, m.Host).Set(float64(health))
+		ch <- prometheus.MustNewConstMetric(o.StatHealth, prometheus.GaugeValue, float64(health), m.Id, m.Host)
+
+		// Error
+
+		errc, err := strconv.ParseFloat(m.StatErrc, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.StatErrc, prometheus.GaugeValue, errc, m.Id, m.Host)
+		}
+		if (err == nil && errc != 0) || strings.TrimSpace(m.StatErrmsg) != "" {
+			ch <- prometheus.MustNewConstMetric(o.StatErrmsg, prometheus.GaugeValue, 1, m.Id, m.Host, fsErrorClass(m.StatErrc, m.StatErrmsg))
+		}
+
+		// Info
+
+		ch <- prometheus.MustNewConstMetric(o.Info, prometheus.GaugeValue, 1, m.Id, m.Host, m.Port, m.Path, m.Uuid, m.Schedgroup, schedgroupSpace(m.Schedgroup), m.StatGeotag)
 	}
 
 	return nil
@@ -587,20 +559,13 @@ func (o *FSCollector) collectFSDF() error {
 
 // Describe sends the descriptors of each FSCollector related metrics we have defined
 func (o *FSCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 	//ch <- o.ScrubbingStateDesc
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *FSCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectFSDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *FSCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectFSDF(ctx, ch)
 }
@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// fsListing is a canned "fs ls -m": a booted filesystem and a drained one.
+const fsListing = "type=fsview host=fst1.example.org port=1095 id=1 uuid=aaaa path=/data01 schedgroup=default.0 stat.boot=booted configstatus=rw stat.errc=0 stat.errmsg= stat.disk.load=0.1 stat.statfs.usedbytes=25 stat.statfs.capacity=100 stat.ropen=3 stat.active=online stat.geotag=site::rack1 stat.health=OK drainstatus=nodrain\n" +
+	"type=fsview host=fst2.example.org port=1095 id=3 uuid=cccc path=/data01 schedgroup=spare stat.boot=down configstatus=empty stat.errc=0 stat.active=offline stat.health=N/A drainstatus=drained\n"
+
+func TestFSCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"fs ls -m": {Stdout: fsListing},
+	})
+
+	got := collect(t, NewFSCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_fs_boot_status{cluster="test",fs="1",node="fst1.example.org"}`:      0,
+		`eos_fs_boot_status{cluster="test",fs="3",node="fst2.example.org"}`:      4,
+		`eos_fs_config_status{cluster="test",fs="1",node="fst1.example.org"}`:    0,
+		`eos_fs_config_status{cluster="test",fs="3",node="fst2.example.org"}`:    3,
+		`eos_fs_disk_load{cluster="test",fs="1",node="fst1.example.org"}`:        0.1,
+		`eos_fs_disk_ropen{cluster="test",fs="1",node="fst1.example.org"}`:       3,
+		`eos_fs_statfs_usedbytes{cluster="test",fs="1",node="fst1.example.org"}`: 25,
+		`eos_fs_statfs_sizebytes{cluster="test",fs="1",node="fst1.example.org"}`: 100,
+		`eos_fs_drain_status{cluster="test",fs="1",node="fst1.example.org"}`:     0,
+		`eos_fs_drain_status{cluster="test",fs="3",node="fst2.example.org"}`:     1,
+		`eos_fs_health{cluster="test",fs="1",node="fst1.example.org"}`:           0,
+		`eos_fs_health{cluster="test",fs="3",node="fst2.example.org"}`:           1,
+		`eos_fs_status{cluster="test",fs="1",node="fst1.example.org"}`:           1,
+		`eos_fs_status{cluster="test",fs="3",node="fst2.example.org"}`:           0,
+	})
+	checkAbsent(t, got, `eos_fs_disk_load{cluster="test",fs="3"`)
+}
+
+func TestSchedgroupSpace(t *testing.T) {
+	tests := map[string]string{
+		"default.0":  "default",
+		"default.12": "default",
+		"ssd.3":      "ssd",
+		"spare":      "spare",
+		"":           "",
+	}
+	for schedgroup, want := range tests {
+		if got := schedgroupSpace(schedgroup); got != want {
+			t.Errorf("schedgroupSpace(%q) = %q, want %q", schedgroup, got, want)
+		}
+	}
+}
+
+func TestFSCollectorInfo(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"fs ls -m": {Stdout: fsListing},
+	})
+
+	got := collect(t, NewFSCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_fs_info{cluster="test",fs="1",geotag="site::rack1",node="fst1.example.org",path="/data01",port="1095",schedgroup="default.0",space="default",uuid="aaaa"}`: 1,
+		`eos_fs_info{cluster="test",fs="3",geotag="",node="fst2.example.org",path="/data01",port="1095",schedgroup="spare",space="spare",uuid="cccc"}`:                  1,
+	})
+}
+
+func TestFSErrorClass(t *testing.T) {
+	tests := []struct {
+		errc, errmsg, want string
+	}{
+		{"5", `"Input/output error"`, "input/output error"},
+		{"19", "statfs failed: No such device", "no such device"},
+		{"30", "Read-only file system", "read-only filesystem"},
+		{"110", "connection timed out", "timeout"},
+		{"28", "", "no space left"},
+		{"13", " ", "permission denied"},
+		{"5", "disk is dying", "other"},
+		{"999", "", "other"},
+	}
+	for _, tt := range tests {
+		if got := fsErrorClass(tt.errc, tt.errmsg); got != tt.want {
+			t.Errorf("fsErrorClass(%q, %q) = %q, want %q", tt.errc, tt.errmsg, got, tt.want)
+		}
+	}
+}
+
+func TestFSCollectorErrors(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"fs ls -m": {Stdout: fsListing +
+			"type=fsview host=fst1.example.org port=1095 id=2 uuid=bbbb path=/data02 schedgroup=default.0 stat.boot=opserror configstatus=ro stat.errc=5 stat.errmsg=\"Input/output error\" stat.active=online\n" +
+			"type=fsview host=fst2.example.org port=1095 id=4 uuid=dddd path=/data02 schedgroup=default.1 stat.boot=opserror configstatus=ro stat.errc=30 stat.active=online\n"},
+	})
+
+	got := collect(t, NewFSCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_fs_error_code{cluster="test",fs="1",node="fst1.example.org"}`:                              0,
+		`eos_fs_error_code{cluster="test",fs="2",node="fst1.example.org"}`:                              5,
+		`eos_fs_error_code{cluster="test",fs="4",node="fst2.example.org"}`:                              30,
+		`eos_fs_error_info{class="input/output error",cluster="test",fs="2",node="fst1.example.org"}`:   1,
+		`eos_fs_error_info{class="read-only filesystem",cluster="test",fs="4",node="fst2.example.org"}`: 1,
+	})
+	// the filesystems without error have no class
+	for name := range got {
+		if strings.HasPrefix(name, "eos_fs_error_info") && (containsLabel(name, "fs", "1") || containsLabel(name, "fs", "3")) {
+			t.Errorf("unexpected %s", name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+type FsckCollector struct {
+	opt *eosclient.Options
+
+	// perFS enables the per filesystem breakdown of the errors.
+	perFS bool
+
+	Errors          *prometheus.Desc
+	FSErrors        *prometheus.Desc
+	ThreadStatus    *prometheus.Desc
+	ReportTimestamp *prometheus.Desc
+}
+
+// NewFsckCollector creates an instance of the FsckCollector.
+// The errors are also broken down per filesystem when perFS is set, which takes an extra report.
+func NewFsckCollector(cluster string, opt *eosclient.Options, perFS bool) *FsckCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &FsckCollector{
+		opt:   opt,
+		perFS: perFS,
+
+		Errors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsck_errors"),
+			"Fsck number of files with errors, by error type",
+			[]string{"type"},
+			labels,
+		),
+		FSErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsck_fs_errors"),
+			"Fsck number of files with errors, by filesystem and error type",
+			[]string{"fs", "type"},
+			labels,
+		),
+		ThreadStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsck_thread_status"),
+			"Fsck thread status: 0=disabled, 1=enabled",
+			[]string{"thread"},
+			labels,
+		),
+		ReportTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsck_report_timestamp_seconds"),
+			"Fsck time of the error collection the report is based on",
+			nil,
+			labels,
+		),
+	}
+}
+
+func (o *FsckCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Errors,
+		o.FSErrors,
+		o.ThreadStatus,
+		o.ReportTimestamp,
+	}
+}
+
+// fsckErrors sums the counts of the report by the given key.
+func fsckErrors(mds []*eosclient.FsckReportInfo, key func(*eosclient.FsckReportInfo) [2]string) map[[2]string]float64 {
+	errors := make(map[[2]string]float64)
+	for _, m := range mds {
+		count, err := strconv.ParseFloat(m.Count, 64)
+		if err == nil {
+			errors[key(m)] += count
+		}
+	}
+	return errors
+}
+
+func (o *FsckCollector) collectFsckDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	stat, err := client.FsckStat(ctx)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.FsckReport(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	var fsmds []*eosclient.FsckReportInfo
+	if o.perFS {
+		fsmds, err = client.FsckReport(ctx, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Threads Status
+
+	for thread, status := range map[string]string{"collection": stat.CollectionThread, "repair": stat.RepairThread} {
+		thread_status := 0
+		switch status {
+		case "enabled":
+			thread_status = 1
+		default:
+			thread_status = 0
+		}
+
+		ch <- prometheus.MustNewConstMetric(o.ThreadStatus, prometheus.GaugeValue, float64(thread_status), thread)
+	}
+
+	timestamp := 0.0
+	for _, m := range mds {
+		ts, err := strconv.ParseFloat(m.Timestamp, 64)
+		if err == nil && ts > timestamp {
+			timestamp = ts
+		}
+	}
+	if timestamp > 0 {
+		ch <- prometheus.MustNewConstMetric(o.ReportTimestamp, prometheus.GaugeValue, timestamp)
+	}
+
+	for k, count := range fsckErrors(mds, func(m *eosclient.FsckReportInfo) [2]string { return [2]string{m.Tag} }) {
+		ch <- prometheus.MustNewConstMetric(o.Errors, prometheus.GaugeValue, count, k[0])
+	}
+
+	for k, count := range fsckErrors(fsmds, func(m *eosclient.FsckReportInfo) [2]string { return [2]string{m.Fsid, m.Tag} }) {
+		ch <- prometheus.MustNewConstMetric(o.FSErrors, prometheus.GaugeValue, count, k[0], k[1])
+	}
+
+	return nil
+
+} // collectFsckDF()
+
+// Describe sends the descriptors of each FsckCollector related metrics we have defined
+func (o *FsckCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *FsckCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectFsckDF(ctx, ch)
+}
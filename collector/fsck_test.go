@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestFsckCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"fsck stat":      {Stdout: "Info: collection thread status -> enabled\nInfo: repair thread status     -> disabled\n"},
+		"fsck report -m": {Stdout: "timestamp=1614596400 tag=\"d_mem_sz_diff\" count=12\ntimestamp=1614596300 tag=\"rep_missing_n\" count=3\n"},
+		"fsck report -a -m": {Stdout: "timestamp=1614596400 fsid=1 tag=\"d_mem_sz_diff\" count=10\n" +
+			"timestamp=1614596400 fsid=2 tag=\"d_mem_sz_diff\" count=2\n" +
+			"timestamp=1614596400 fsid=2 tag=\"d_mem_sz_diff\" count=1\n"},
+	})
+
+	tests := []struct {
+		perFS bool
+		want  map[string]float64
+	}{
+		{false, map[string]float64{
+			`eos_fsck_thread_status{cluster="test",thread="collection"}`: 1,
+			`eos_fsck_thread_status{cluster="test",thread="repair"}`:     0,
+			`eos_fsck_report_timestamp_seconds{cluster="test"}`:          1614596400,
+			`eos_fsck_errors{cluster="test",type="d_mem_sz_diff"}`:       12,
+			`eos_fsck_errors{cluster="test",type="rep_missing_n"}`:       3,
+		}},
+		{true, map[string]float64{
+			`eos_fsck_errors{cluster="test",type="d_mem_sz_diff"}`:           12,
+			`eos_fsck_fs_errors{cluster="test",fs="1",type="d_mem_sz_diff"}`: 10,
+			`eos_fsck_fs_errors{cluster="test",fs="2",type="d_mem_sz_diff"}`: 3,
+		}},
+	}
+	for _, tt := range tests {
+		got := collect(t, NewFsckCollector("test", opt, tt.perFS))
+		checkSeries(t, got, tt.want)
+		if !tt.perFS {
+			checkAbsent(t, got, "eos_fsck_fs_errors")
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+type FusexCollector struct {
+	opt *eosclient.Options
+
+	Clients            *prometheus.Desc
+	HostClients        *prometheus.Desc
+	ClientState        *prometheus.Desc
+	ClientCaps         *prometheus.Desc
+	ClientHeartbeatAge *prometheus.Desc
+}
+
+// NewFusexCollector creates an instance of the FusexCollector
+func NewFusexCollector(cluster string, opt *eosclient.Options) *FusexCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	clientLabels := []string{"host", "uuid", "mount"}
+	return &FusexCollector{
+		opt: opt,
+
+		Clients: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fusex_clients"),
+			"Fusex number of clients, by eosxd version",
+			[]string{"version"},
+			labels,
+		),
+		HostClients: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fusex_host_clients"),
+			"Fusex number of clients, by client host",
+			[]string{"host"},
+			labels,
+		),
+		ClientState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fusex_client_state"),
+			"Fusex client state, e.g. online, evicted or locked, as a label of value 1",
+			[]string{"host", "uuid", "mount", "state"},
+			labels,
+		),
+		ClientCaps: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fusex_client_caps"),
+			"Fusex number of caps held by the client",
+			clientLabels,
+			labels,
+		),
+		ClientHeartbeatAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fusex_client_heartbeat_age_seconds"),
+			"Fusex time since the last heartbeat of the client",
+			clientLabels,
+			labels,
+		),
+	}
+}
+
+func (o *FusexCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Clients,
+		o.HostClients,
+		o.ClientState,
+		o.ClientCaps,
+		o.ClientHeartbeatAge,
+	}
+}
+
+func (o *FusexCollector) collectFusexDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.ListFusex(ctx)
+	if err != nil {
+		return err
+	}
+
+	versions := make(map[string]float64)
+	hosts := make(map[string]float64)
+
+	for _, m := range mds {
+		versions[m.Version]++
+		hosts[m.Host]++
+
+		ch <- prometheus.MustNewConstMetric(o.ClientState, prometheus.GaugeValue, 1, m.Host, m.Uuid, m.Mount, m.State)
+
+		caps, err := strconv.ParseFloat(m.Caps, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.ClientCaps, prometheus.GaugeValue, caps, m.Host, m.Uuid, m.Mount)
+		}
+
+		delta, err := strconv.ParseFloat(m.Delta, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.ClientHeartbeatAge, prometheus.GaugeValue, delta, m.Host, m.Uuid, m.Mount)
+		}
+	}
+
+	for version, count := range versions {
+		ch <- prometheus.MustNewConstMetric(o.Clients, prometheus.GaugeValue, count, version)
+	}
+
+	for host, count := range hosts {
+		ch <- prometheus.MustNewConstMetric(o.HostClients, prometheus.GaugeValue, count, host)
+	}
+
+	return nil
+
+} // collectFusexDF()
+
+// Describe sends the descriptors of each FusexCollector related metrics we have defined
+func (o *FusexCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *FusexCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectFusexDF(ctx, ch)
+}
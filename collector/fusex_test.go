@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestFusexCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"fusex ls -m": {Stdout: "client=eosxd host=lxplus1.cern.ch version=4.8.40 state=online tstart=1614596400 tsnow=1614600000 delta=0.25 uuid=a1 pid=100 caps=12 fds=3 type=static mount=/eos\n" +
+			"client=eosxd host=lxplus1.cern.ch version=4.8.51 state=locked tstart=1614596400 tsnow=1614600000 delta=120.5 uuid=b2 pid=200 caps=3 fds=0 type=static mount=/eos/user\n" +
+			"client=eosxd host=lxplus2.cern.ch version=4.8.51 state=evicted tstart=1614596400 tsnow=1614600000 delta=3600 uuid=c3 pid=300 caps=0 fds=0 type=autofs mount=/eos\n"},
+	})
+
+	got := collect(t, NewFusexCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_fusex_clients{cluster="test",version="4.8.40"}`:                                                   1,
+		`eos_fusex_clients{cluster="test",version="4.8.51"}`:                                                   2,
+		`eos_fusex_host_clients{cluster="test",host="lxplus1.cern.ch"}`:                                        2,
+		`eos_fusex_host_clients{cluster="test",host="lxplus2.cern.ch"}`:                                        1,
+		`eos_fusex_client_state{cluster="test",host="lxplus1.cern.ch",mount="/eos",state="online",uuid="a1"}`:  1,
+		`eos_fusex_client_state{cluster="test",host="lxplus2.cern.ch",mount="/eos",state="evicted",uuid="c3"}`: 1,
+		`eos_fusex_client_caps{cluster="test",host="lxplus1.cern.ch",mount="/eos/user",uuid="b2"}`:             3,
+		`eos_fusex_client_heartbeat_age_seconds{cluster="test",host="lxplus1.cern.ch",mount="/eos",uuid="a1"}`: 0.25,
+		`eos_fusex_client_heartbeat_age_seconds{cluster="test",host="lxplus2.cern.ch",mount="/eos",uuid="c3"}`: 3600,
+	})
+}
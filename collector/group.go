@@ -2,7 +2,6 @@ package collector
 
 import (
 	"context"
-	"log"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -10,251 +9,185 @@ import (
 )
 
 type GroupCollector struct {
-	Name                   *prometheus.GaugeVec
-	CfgStatus              *prometheus.GaugeVec
-	Nofs                   *prometheus.GaugeVec
-	AvgStatDiskLoad        *prometheus.GaugeVec
-	SigStatDiskLoad        *prometheus.GaugeVec
-	SumStatDiskReadratemb  *prometheus.GaugeVec
-	SumStatDiskWriteratemb *prometheus.GaugeVec
-	SumStatNetEthratemib   *prometheus.GaugeVec
-	SumStatNetInratemib    *prometheus.GaugeVec
-	SumStatNetOutratemib   *prometheus.GaugeVec
-	SumStatRopen           *prometheus.GaugeVec
-	SumStatWopen           *prometheus.GaugeVec
-	SumStatStatfsUsedbytes *prometheus.GaugeVec
-	SumStatStatfsFreebytes *prometheus.GaugeVec
-	SumStatStatfsCapacity  *prometheus.GaugeVec
-	SumStatUsedfiles       *prometheus.GaugeVec
-	SumStatStatfsFfree     *prometheus.GaugeVec
-	SumStatStatfsFiles     *prometheus.GaugeVec
-	DevStatStatfsFilled    *prometheus.GaugeVec
-	AvgStatStatfsFilled    *prometheus.GaugeVec
-	SigStatStatfsFilled    *prometheus.GaugeVec
-	CfgStatBalancing       *prometheus.GaugeVec
-	SumStatBalancerRunning *prometheus.GaugeVec
-	SumStatDrainerRunning  *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	Name                   *prometheus.Desc
+	CfgStatus              *prometheus.Desc
+	Nofs                   *prometheus.Desc
+	AvgStatDiskLoad        *prometheus.Desc
+	SigStatDiskLoad        *prometheus.Desc
+	SumStatDiskReadratemb  *prometheus.Desc
+	SumStatDiskWriteratemb *prometheus.Desc
+	SumStatNetEthratemib   *prometheus.Desc
+	SumStatNetInratemib    *prometheus.Desc
+	SumStatNetOutratemib   *prometheus.Desc
+	SumStatRopen           *prometheus.Desc
+	SumStatWopen           *prometheus.Desc
+	SumStatStatfsUsedbytes *prometheus.Desc
+	SumStatStatfsFreebytes *prometheus.Desc
+	SumStatStatfsCapacity  *prometheus.Desc
+	SumStatUsedfiles       *prometheus.Desc
+	SumStatStatfsFfree     *prometheus.Desc
+	SumStatStatfsFiles     *prometheus.Desc
+	DevStatStatfsFilled    *prometheus.Desc
+	AvgStatStatfsFilled    *prometheus.Desc
+	SigStatStatfsFilled    *prometheus.Desc
+	CfgStatBalancing       *prometheus.Desc
+	SumStatBalancerRunning *prometheus.Desc
+	SumStatDrainerRunning  *prometheus.Desc
 }
 
 //NewGroupCollector creates an cluster of the GroupCollector and instantiates
 // the individual metrics that show information about the Group.
-func NewGroupCollector(cluster string) *GroupCollector {
+func NewGroupCollector(cluster string, opt *eosclient.Options) *GroupCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &GroupCollector{
-		CfgStatus: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_cfg_status",
-				Help:        "Group Status 0=off, 1=on",
-				ConstLabels: labels,
-			},
+		opt: opt,
+		CfgStatus: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_cfg_status"),
+			"Group Status 0=off, 1=on",
 			[]string{"group"},
+			labels,
 		),
-		Nofs: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_nofs",
-				Help:        "Number of filesystems in the group",
-				ConstLabels: labels,
-			},
+		Nofs: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_nofs"),
+			"Number of filesystems in the group",
 			[]string{"group"},
+			labels,
 		),
-		AvgStatDiskLoad: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_load_avg",
-				Help:        "Group Avg Stat disk load",
-				ConstLabels: labels,
-			},
+		AvgStatDiskLoad: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_load_avg"),
+			"Group Avg Stat disk load",
 			[]string{"group"},
+			labels,
 		),
-		SigStatDiskLoad: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_load_sig",
-				Help:        "Group Sig Stat disk load",
-				ConstLabels: labels,
-			},
+		SigStatDiskLoad: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_load_sig"),
+			"Group Sig Stat disk load",
 			[]string{"group"},
+			labels,
 		),
-		SumStatDiskReadratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_readratemb",
-				Help:        "Group Sum Stat Disk Read Rate in MB/s",
-				ConstLabels: labels,
-			},
+		SumStatDiskReadratemb: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_readratemb"),
+			"Group Sum Stat Disk Read Rate in MB/s",
 			[]string{"group"},
+			labels,
 		),
-		SumStatDiskWriteratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_writeratemb",
-				Help:        "Group Sum Stat Disk Write Rate in MB/s",
-				ConstLabels: labels,
-			},
+		SumStatDiskWriteratemb: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_writeratemb"),
+			"Group Sum Stat Disk Write Rate in MB/s",
 			[]string{"group"},
+			labels,
 		),
-		SumStatNetEthratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_net_ethratemib",
-				Help:        "Group Stat Net Eth Rate in MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetEthratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_net_ethratemib"),
+			"Group Stat Net Eth Rate in MiB/s",
 			[]string{"group"},
+			labels,
 		),
-		SumStatNetInratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_net_inratemib",
-				Help:        "Group Stat Net In Rate MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetInratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_net_inratemib"),
+			"Group Stat Net In Rate MiB/s",
 			[]string{"group"},
+			labels,
 		),
-		SumStatNetOutratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_net_outratemib",
-				Help:        "Group Stat Net Out Rate MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetOutratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_net_outratemib"),
+			"Group Stat Net Out Rate MiB/s",
 			[]string{"group"},
+			labels,
 		),
-		SumStatRopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_ropen",
-				Help:        "Group Open reads",
-				ConstLabels: labels,
-			},
+		SumStatRopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_ropen"),
+			"Group Open reads",
 			[]string{"group"},
+			labels,
 		),
-		SumStatWopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_disk_wopen",
-				Help:        "Group Open writes",
-				ConstLabels: labels,
-			},
+		SumStatWopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_disk_wopen"),
+			"Group Open writes",
 			[]string{"group"},
+			labels,
 		),
-		SumStatStatfsUsedbytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_usedbytes",
-				Help:        "Group StatFs Used Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatfsUsedbytes: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_usedbytes"),
+			"Group StatFs Used Bytes",
 			[]string{"group"},
+			labels,
 		),
-		SumStatStatfsFreebytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_freebytes",
-				Help:        "Group StatFs Free Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFreebytes: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_freebytes"),
+			"Group StatFs Free Bytes",
 			[]string{"group"},
+			labels,
 		),
-		SumStatStatfsCapacity: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_sizebytes",
-				Help:        "Group StatFs Capacity",
-				ConstLabels: labels,
-			},
+		SumStatStatfsCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_sizebytes"),
+			"Group StatFs Capacity",
 			[]string{"group"},
+			labels,
 		),
-		SumStatUsedfiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_usedfiles",
-				Help:        "Group Used Files",
-				ConstLabels: labels,
-			},
+		SumStatUsedfiles: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_usedfiles"),
+			"Group Used Files",
 			[]string{"group"},
+			labels,
 		),
-		SumStatStatfsFfree: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_freefiles",
-				Help:        "Group Free-Files",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFfree: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_freefiles"),
+			"Group Free-Files",
 			[]string{"group"},
+			labels,
 		),
-		SumStatStatfsFiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_totalfiles",
-				Help:        "Group Files",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFiles: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_totalfiles"),
+			"Group Files",
 			[]string{"group"},
+			labels,
 		),
-		DevStatStatfsFilled: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_filled_dev",
-				Help:        "Group Dev Filled",
-				ConstLabels: labels,
-			},
+		DevStatStatfsFilled: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_filled_dev"),
+			"Group Dev Filled",
 			[]string{"group"},
+			labels,
 		),
-		AvgStatStatfsFilled: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_filled_avg",
-				Help:        "Group Avg Filled",
-				ConstLabels: labels,
-			},
+		AvgStatStatfsFilled: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_filled_avg"),
+			"Group Avg Filled",
 			[]string{"group"},
+			labels,
 		),
-		SigStatStatfsFilled: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "group_statfs_filled_sig",
-				Help:        "Group Sig Filled",
-				ConstLabels: labels,
-			},
+		SigStatStatfsFilled: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "group_statfs_filled_sig"),
+			"Group Sig Filled",
 			[]string{"group"},
+			labels,
 		),
-		CfgStatBalancing: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "group_balancer_status",
-				Help:        "Status of group balancing 0=idle, 1=balancing, 2=drainwait",
-				ConstLabels: labels,
-			},
+		CfgStatBalancing: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "group_balancer_status"),
+			"Status of group balancing 0=idle, 1=balancing, 2=drainwait",
 			[]string{"group"},
+			labels,
 		),
-		SumStatBalancerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "group_balancer_running",
-				Help:        "Group Stat Balancer Running",
-				ConstLabels: labels,
-			},
+		SumStatBalancerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "group_balancer_running"),
+			"Group Stat Balancer Running",
 			[]string{"group"},
+			labels,
 		),
-		SumStatDrainerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "group_drainer_running",
-				Help:        "Group Stat Drainer Running",
-				ConstLabels: labels,
-			},
+		SumStatDrainerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "group_drainer_running"),
+			"Group Stat Drainer Running",
 			[]string{"group"},
+			labels,
 		),
 	}
 }
 
-func (o *GroupCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *GroupCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.CfgStatus,
 		o.Nofs,
 		o.AvgStatDiskLoad,
@@ -281,18 +214,15 @@ func (o *GroupCollector) collectorList() []prometheus.Collector {
 	}
 }
 
-func (o *GroupCollector) collectGroupDF() error {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
+func (o *GroupCollector) collectGroupDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	mds, err := client.ListGroup(context.Background(), "root")
+	mds, err := client.ListGroup(ctx)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	for _, m := range mds {
@@ -303,101 +233,101 @@ func (o *GroupCollector) collectGroupDF() error {
 		}
 
 		status := float64(cfgstatus)
-		o.CfgStatus.WithLabelValues(m.Name).Set(status)
+		ch <- prometheus.MustNewConstMetric(o.CfgStatus, prometheus.GaugeValue, status, m.Name)
 
 		nofs, err := strconv.ParseFloat(m.Nofs, 64)
 		if err == nil {
-			o.Nofs.WithLabelValues(m.Name).Set(nofs)
+			ch <- prometheus.MustNewConstMetric(o.Nofs, prometheus.GaugeValue, nofs, m.Name)
 		}
 
 		avgdl, err := strconv.ParseFloat(m.AvgStatDiskLoad, 64)
 		if err == nil {
-			o.AvgStatDiskLoad.WithLabelValues(m.Name).Set(avgdl)
+			ch <- prometheus.MustNewConstMetric(o.AvgStatDiskLoad, prometheus.GaugeValue, avgdl, m.Name)
 		}
 
 		sigdl, err := strconv.ParseFloat(m.SigStatDiskLoad, 64)
 		if err == nil {
-			o.SigStatDiskLoad.WithLabelValues(m.Name).Set(sigdl)
+			ch <- prometheus.MustNewConstMetric(o.SigStatDiskLoad, prometheus.GaugeValue, sigdl, m.Name)
 		}
 
 		sumdiskr, err := strconv.ParseFloat(m.SumStatDiskReadratemb, 64)
 		if err == nil {
-			o.SumStatDiskReadratemb.WithLabelValues(m.Name).Set(sumdiskr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskReadratemb, prometheus.GaugeValue, sumdiskr, m.Name)
 		}
 
 		sumdiskw, err := strconv.ParseFloat(m.SumStatDiskWriteratemb, 64)
 		if err == nil {
-			o.SumStatDiskWriteratemb.WithLabelValues(m.Name).Set(sumdiskw)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskWriteratemb, prometheus.GaugeValue, sumdiskw, m.Name)
 		}
 
 		sumethrate, err := strconv.ParseFloat(m.SumStatNetEthratemib, 64)
 		if err == nil {
-			o.SumStatNetEthratemib.WithLabelValues(m.Name).Set(sumethrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetEthratemib, prometheus.GaugeValue, sumethrate, m.Name)
 		}
 
 		suminrate, err := strconv.ParseFloat(m.SumStatNetInratemib, 64)
 		if err == nil {
-			o.SumStatNetInratemib.WithLabelValues(m.Name).Set(suminrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetInratemib, prometheus.GaugeValue, suminrate, m.Name)
 		}
 
 		sumoutrate, err := strconv.ParseFloat(m.SumStatNetOutratemib, 64)
 		if err == nil {
-			o.SumStatNetOutratemib.WithLabelValues(m.Name).Set(sumoutrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetOutratemib, prometheus.GaugeValue, sumoutrate, m.Name)
 		}
 
 		ropen, err := strconv.ParseFloat(m.SumStatRopen, 64)
 		if err == nil {
-			o.SumStatRopen.WithLabelValues(m.Name).Set(ropen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatRopen, prometheus.GaugeValue, ropen, m.Name)
 		}
 
 		wopen, err := strconv.ParseFloat(m.SumStatWopen, 64)
 		if err == nil {
-			o.SumStatWopen.WithLabelValues(m.Name).Set(wopen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatWopen, prometheus.GaugeValue, wopen, m.Name)
 		}
 
 		usedb, err := strconv.ParseFloat(m.SumStatStatfsUsedbytes, 64)
 		if err == nil {
-			o.SumStatStatfsUsedbytes.WithLabelValues(m.Name).Set(usedb)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsUsedbytes, prometheus.GaugeValue, usedb, m.Name)
 		}
 
 		fbytes, err := strconv.ParseFloat(m.SumStatStatfsFreebytes, 64)
 		if err == nil {
-			o.SumStatStatfsFreebytes.WithLabelValues(m.Name).Set(fbytes)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFreebytes, prometheus.GaugeValue, fbytes, m.Name)
 		}
 
 		fscap, err := strconv.ParseFloat(m.SumStatStatfsCapacity, 64)
 		if err == nil {
-			o.SumStatStatfsCapacity.WithLabelValues(m.Name).Set(fscap)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsCapacity, prometheus.GaugeValue, fscap, m.Name)
 		}
 
 		ufiles, err := strconv.ParseFloat(m.SumStatUsedfiles, 64)
 		if err == nil {
-			o.SumStatUsedfiles.WithLabelValues(m.Name).Set(ufiles)
+			ch <- prometheus.MustNewConstMetric(o.SumStatUsedfiles, prometheus.GaugeValue, ufiles, m.Name)
 		}
 
 		ffree, err := strconv.ParseFloat(m.SumStatStatfsFfree, 64)
 		if err == nil {
-			o.SumStatStatfsFfree.WithLabelValues(m.Name).Set(ffree)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFfree, prometheus.GaugeValue, ffree, m.Name)
 		}
 
 		files, err := strconv.ParseFloat(m.SumStatStatfsFiles, 64)
 		if err == nil {
-			o.SumStatStatfsFiles.WithLabelValues(m.Name).Set(files)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFiles, prometheus.GaugeValue, files, m.Name)
 		}
 
 		devfilled, err := strconv.ParseFloat(m.DevStatStatfsFilled, 64)
 		if err == nil {
-			o.DevStatStatfsFilled.WithLabelValues(m.Name).Set(devfilled)
+			ch <- prometheus.MustNewConstMetric(o.DevStatStatfsFilled, prometheus.GaugeValue, devfilled, m.Name)
 		}
 
 		avgfilled, err := strconv.ParseFloat(m.AvgStatStatfsFilled, 64)
 		if err == nil {
-			o.AvgStatStatfsFilled.WithLabelValues(m.Name).Set(avgfilled)
+			ch <- prometheus.MustNewConstMetric(o.AvgStatStatfsFilled, prometheus.GaugeValue, avgfilled, m.Name)
 		}
 
 		sigfilled, err := strconv.ParseFloat(m.SigStatStatfsFilled, 64)
 		if err == nil {
-			o.SigStatStatfsFilled.WithLabelValues(m.Name).Set(sigfilled)
+			ch <- prometheus.MustNewConstMetric(o.SigStatStatfsFilled, prometheus.GaugeValue, sigfilled, m.Name)
 		}
 
 		// Balancer Status.
@@ -414,16 +344,16 @@ func (o *GroupCollector) collectGroupDF() error {
 			balancer_status = 0
 		}
 
-		o.CfgStatBalancing.WithLabelValues(m.Name).Set(float64(balancer_status))
+		ch <- prometheus.MustNewConstMetric(o.CfgStatBalancing, prometheus.GaugeValue, float64(balancer_status), m.Name)
 
 		balr, err := strconv.ParseFloat(m.SumStatBalancerRunning, 64)
 		if err == nil {
-			o.SumStatBalancerRunning.WithLabelValues(m.Name).Set(balr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatBalancerRunning, prometheus.GaugeValue, balr, m.Name)
 		}
 
 		drainr, err := strconv.ParseFloat(m.SumStatDrainerRunning, 64)
 		if err == nil {
-			o.SumStatDrainerRunning.WithLabelValues(m.Name).Set(drainr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDrainerRunning, prometheus.GaugeValue, drainr, m.Name)
 		}
 	}
 
@@ -433,19 +363,12 @@ func (o *GroupCollector) collectGroupDF() error {
 
 // Describe sends the descriptors of each GroupCollector related metrics we have defined
 func (o *GroupCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *GroupCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectGroupDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *GroupCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectGroupDF(ctx, ch)
 }
@@ -0,0 +1,30 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestGroupCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"group ls -m": {Stdout: "type=groupview name=default.0 cfg.status=on nofs=2 avg.stat.disk.load=0.2 sum.stat.statfs.usedbytes=50 sum.stat.statfs.capacity=150 cfg.stat.balancing=idle sum.stat.balancer.running=0 dev.stat.statfs.filled=1.5\n" +
+			"type=groupview name=default.1 cfg.status=off nofs=1 sum.stat.statfs.usedbytes=5 cfg.stat.balancing=balancing sum.stat.balancer.running=3\n"},
+	})
+
+	got := collect(t, NewGroupCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_group_cfg_status{cluster="test",group="default.0"}`:        1,
+		`eos_group_cfg_status{cluster="test",group="default.1"}`:        0,
+		`eos_group_nofs{cluster="test",group="default.0"}`:              2,
+		`eos_group_nofs{cluster="test",group="default.1"}`:              1,
+		`eos_group_disk_load_avg{cluster="test",group="default.0"}`:     0.2,
+		`eos_group_statfs_usedbytes{cluster="test",group="default.1"}`:  5,
+		`eos_group_statfs_sizebytes{cluster="test",group="default.0"}`:  150,
+		`eos_group_statfs_filled_dev{cluster="test",group="default.0"}`: 1.5,
+		`eos_group_balancer_status{cluster="test",group="default.0"}`:   0,
+		`eos_group_balancer_status{cluster="test",group="default.1"}`:   1,
+		`eos_group_balancer_running{cluster="test",group="default.1"}`:  3,
+	})
+	checkAbsent(t, got, `eos_group_statfs_sizebytes{cluster="test",group="default.1"}`)
+}
@@ -0,0 +1,149 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// ioDirections maps the byte measurements of io stat to the direction label.
+var ioDirections = map[string]string{
+	"bytes_read":    "read",
+	"bytes_written": "write",
+	"bytes_write":   "write",
+}
+
+type IOCollector struct {
+	opt *eosclient.Options
+
+	// perUser enables the breakdown of the traffic per uid and gid.
+	perUser bool
+
+	Bytes            *prometheus.Desc
+	ApplicationBytes *prometheus.Desc
+	DomainBytes      *prometheus.Desc
+	UidBytes         *prometheus.Desc
+	GidBytes         *prometheus.Desc
+}
+
+// NewIOCollector creates an instance of the IOCollector.
+// The traffic is also broken down per uid and gid when perUser is set.
+func NewIOCollector(cluster string, opt *eosclient.Options, perUser bool) *IOCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &IOCollector{
+		opt:     opt,
+		perUser: perUser,
+
+		Bytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "io_bytes_total"),
+			"IO bytes read or written since the start of the MGM",
+			[]string{"direction"},
+			labels,
+		),
+		ApplicationBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "io_application_bytes_total"),
+			"IO bytes read or written, by application tag",
+			[]string{"application", "direction"},
+			labels,
+		),
+		DomainBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "io_domain_bytes_total"),
+			"IO bytes read or written, by client domain",
+			[]string{"domain", "direction"},
+			labels,
+		),
+		UidBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "io_uid_bytes_total"),
+			"IO bytes read or written, by uid",
+			[]string{"uid", "direction"},
+			labels,
+		),
+		GidBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "io_gid_bytes_total"),
+			"IO bytes read or written, by gid",
+			[]string{"gid", "direction"},
+			labels,
+		),
+	}
+}
+
+func (o *IOCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Bytes,
+		o.ApplicationBytes,
+		o.DomainBytes,
+		o.UidBytes,
+		o.GidBytes,
+	}
+}
+
+// ioMetric returns the metric of an io stat line, by what the line is broken down by.
+func (o *IOCollector) ioMetric(m *eosclient.IOStatInfo, direction string, total float64) prometheus.Metric {
+	switch {
+	case m.Application != "":
+		return prometheus.MustNewConstMetric(o.ApplicationBytes, prometheus.CounterValue, total, m.Application, direction)
+	case m.Domain != "":
+		return prometheus.MustNewConstMetric(o.DomainBytes, prometheus.CounterValue, total, m.Domain, direction)
+	case m.Uid != "" && m.Uid != "all":
+		return prometheus.MustNewConstMetric(o.UidBytes, prometheus.CounterValue, total, m.Uid, direction)
+	case m.Gid != "" && m.Gid != "all":
+		return prometheus.MustNewConstMetric(o.GidBytes, prometheus.CounterValue, total, m.Gid, direction)
+	default:
+		return prometheus.MustNewConstMetric(o.Bytes, prometheus.CounterValue, total, direction)
+	}
+}
+
+func (o *IOCollector) collectIODF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.IOStat(ctx, o.perUser)
+	if err != nil {
+		return err
+	}
+
+	details, err := client.IOStatDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the totals of the detailed listing are already in the summary one
+	for _, m := range details {
+		if m.Application != "" || m.Domain != "" {
+			mds = append(mds, m)
+		}
+	}
+
+	for _, m := range mds {
+		direction, ok := ioDirections[m.Measurement]
+		if !ok {
+			continue
+		}
+
+		total, err := strconv.ParseFloat(m.Total, 64)
+		if err == nil {
+			ch <- o.ioMetric(m, direction, total)
+		}
+	}
+
+	return nil
+
+} // collectIODF()
+
+// Describe sends the descriptors of each IOCollector related metrics we have defined
+func (o *IOCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *IOCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectIODF(ctx, ch)
+}
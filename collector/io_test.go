@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestIOCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"io stat -m": {Stdout: "uid=all gid=all measurement=bytes_read total=1000 60s=10 300s=50 3600s=500 86400s=1000\n" +
+			"uid=all gid=all measurement=bytes_written total=400 60s=4 300s=20 3600s=200 86400s=400\n" +
+			"uid=all gid=all measurement=read_calls total=7 60s=1 300s=2 3600s=3 86400s=7\n"},
+		"io stat -a -m": {Stdout: "uid=all gid=all measurement=bytes_read total=1000 60s=10 300s=50 3600s=500 86400s=1000\n" +
+			"uid=all gid=all measurement=bytes_written total=400 60s=4 300s=20 3600s=200 86400s=400\n" +
+			"uid=1000 measurement=bytes_read total=600 60s=6 300s=30 3600s=300 86400s=600\n" +
+			"gid=1000 measurement=bytes_written total=400 60s=4 300s=20 3600s=200 86400s=400\n"},
+		// the detailed listing repeats the totals, which are already in the summary
+		"io stat -x -m": {Stdout: "uid=all gid=all measurement=bytes_read total=1000 60s=10 300s=50 3600s=500 86400s=1000\n" +
+			"measurement=bytes_read application=eoscp total=800 60s=8 300s=40 3600s=400 86400s=800\n" +
+			"measurement=bytes_written app=xrdcp total=50 60s=0 300s=0 3600s=0 86400s=50\n" +
+			"measurement=bytes_read domain=.cern.ch total=900 60s=9 300s=45 3600s=450 86400s=900\n"},
+	})
+
+	tests := []struct {
+		perUser bool
+		want    map[string]float64
+	}{
+		{false, map[string]float64{
+			`eos_io_bytes_total{cluster="test",direction="read"}`:                                  1000,
+			`eos_io_bytes_total{cluster="test",direction="write"}`:                                 400,
+			`eos_io_application_bytes_total{application="eoscp",cluster="test",direction="read"}`:  800,
+			`eos_io_application_bytes_total{application="xrdcp",cluster="test",direction="write"}`: 50,
+			`eos_io_domain_bytes_total{cluster="test",direction="read",domain=".cern.ch"}`:         900,
+		}},
+		{true, map[string]float64{
+			`eos_io_bytes_total{cluster="test",direction="read"}`:                 1000,
+			`eos_io_uid_bytes_total{cluster="test",direction="read",uid="1000"}`:  600,
+			`eos_io_gid_bytes_total{cluster="test",direction="write",gid="1000"}`: 400,
+		}},
+	}
+	for _, tt := range tests {
+		got := collect(t, NewIOCollector("test", opt, tt.perUser))
+		checkSeries(t, got, tt.want)
+		if !tt.perUser {
+			checkAbsent(t, got, "eos_io_uid_bytes_total", "eos_io_gid_bytes_total")
+		}
+	}
+}
@@ -2,7 +2,6 @@ package collector
 
 import (
 	"context"
-	"log"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,145 +12,155 @@ const (
 	nodeLabelFormat = "node.%v"
 )
 
+// nodeStatuses are the states of the eos_node_status state set. Any other status is reported as unknown.
+var nodeStatuses = []string{"online", "offline", "unknown"}
+
 type NodeCollector struct {
+	opt *eosclient.Options
 
 	// UsedBytes displays the total used bytes in the Node
-	Hostport              *prometheus.GaugeVec
-	Status                *prometheus.GaugeVec
-	Nofs                  *prometheus.GaugeVec
-	SumStatStatfsFree     *prometheus.GaugeVec
-	SumStatStatfsUsed     *prometheus.GaugeVec
-	SumStatStatfsTotal    *prometheus.GaugeVec
-	SumStatStatFilesFree  *prometheus.GaugeVec
-	SumStatStatFilesUsed  *prometheus.GaugeVec
-	SumStatStatFilesTotal *prometheus.GaugeVec
-	SumStatRopen          *prometheus.GaugeVec
-	SumStatWopen          *prometheus.GaugeVec
-	CfgStatSysThreads     *prometheus.GaugeVec
-	SumStatNetInratemib   *prometheus.GaugeVec
-	SumStatNetOutratemib  *prometheus.GaugeVec
+	Hostport              *prometheus.Desc
+	Status                *prometheus.Desc
+	Nofs                  *prometheus.Desc
+	SumStatStatfsFree     *prometheus.Desc
+	SumStatStatfsUsed     *prometheus.Desc
+	SumStatStatfsTotal    *prometheus.Desc
+	SumStatStatFilesFree  *prometheus.Desc
+	SumStatStatFilesUsed  *prometheus.Desc
+	SumStatStatFilesTotal *prometheus.Desc
+	SumStatRopen          *prometheus.Desc
+	SumStatWopen          *prometheus.Desc
+	CfgStatSysThreads     *prometheus.Desc
+	SumStatNetInratemib   *prometheus.Desc
+	SumStatNetOutratemib  *prometheus.Desc
+	HeartbeatAge          *prometheus.Desc
+	CfgStatus             *prometheus.Desc
+	Info                  *prometheus.Desc
+	CfgGwNtx              *prometheus.Desc
+	CfgGwRate             *prometheus.Desc
 }
 
 //NewNodeCollector creates an cluster of the NodeCollector
-func NewNodeCollector(cluster string) *NodeCollector {
+func NewNodeCollector(cluster string, opt *eosclient.Options) *NodeCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 
 	return &NodeCollector{
+		opt: opt,
 
-		Nofs: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_nofs",
-				Help:        "Node Number of filesystems",
-				ConstLabels: labels,
-			},
+		Nofs: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_nofs"),
+			"Node Number of filesystems",
+			[]string{"node"},
+			labels,
+		),
+		SumStatStatfsFree: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_freebytes"),
+			"Node Free Bytes",
+			[]string{"node"},
+			labels,
+		),
+		SumStatStatfsUsed: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_usedbytes"),
+			"Node Used Bytes",
+			[]string{"node"},
+			labels,
+		),
+		SumStatStatfsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_sizebytes"),
+			"Node Total Bytes",
+			[]string{"node"},
+			labels,
+		),
+		SumStatStatFilesFree: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_freefiles"),
+			"Node Free Files",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatfsFree: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_freebytes",
-				Help:        "Node Free Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatFilesUsed: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_usedfiles"),
+			"Node Used Files",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatfsUsed: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_usedbytes",
-				Help:        "Node Used Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatFilesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_statfs_totalfiles"),
+			"Node Total Files",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatfsTotal: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_sizebytes",
-				Help:        "Node Total Bytes",
-				ConstLabels: labels,
-			},
+		SumStatRopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_disk_ropen"),
+			"Node Open reads",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatFilesFree: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_freefiles",
-				Help:        "Node Free Files",
-				ConstLabels: labels,
-			},
+		SumStatWopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_disk_wopen"),
+			"Node Open writes",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatFilesUsed: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_usedfiles",
-				Help:        "Node Used Files",
-				ConstLabels: labels,
-			},
+		CfgStatSysThreads: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_threads"),
+			"Node Number of threads",
 			[]string{"node"},
+			labels,
 		),
-		SumStatStatFilesTotal: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_statfs_totalfiles",
-				Help:        "Node Total Files",
-				ConstLabels: labels,
-			},
+		SumStatNetInratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_net_inratemib"),
+			"Node Net in Rate in Mib",
 			[]string{"node"},
+			labels,
 		),
-		SumStatRopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_disk_ropen",
-				Help:        "Node Open reads",
-				ConstLabels: labels,
-			},
+		SumStatNetOutratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_net_outratemib"),
+			"Node Net out Rate in Mib",
 			[]string{"node"},
+			labels,
+		),
+		Status: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_status"),
+			"Node Status: 1 for the current status among online, offline and unknown, 0 for the others",
+			[]string{"node", "status"},
+			labels,
 		),
-		SumStatWopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_disk_wopen",
-				Help:        "Node Open writes",
-				ConstLabels: labels,
-			},
+		HeartbeatAge: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_heartbeat_age_seconds"),
+			"Node Time since the last heartbeat",
 			[]string{"node"},
+			labels,
 		),
-		CfgStatSysThreads: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_threads",
-				Help:        "Node Number of threads",
-				ConstLabels: labels,
-			},
+		CfgStatus: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_config_status"),
+			"Node Config status: 1=on, 0=off",
 			[]string{"node"},
+			labels,
 		),
-		SumStatNetInratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_net_inratemib",
-				Help:        "Node Net in Rate in Mib",
-				ConstLabels: labels,
-			},
+		Info: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_info"),
+			"Node Geotag and whether the node is a transfer gateway (txgw), always 1",
+			[]string{"node", "geotag", "txgw"},
+			labels,
+		),
+		CfgGwNtx: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_gateway_ntx"),
+			"Node Number of parallel transfers allowed on the transfer gateway",
 			[]string{"node"},
+			labels,
 		),
-		SumStatNetOutratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "node_net_outratemib",
-				Help:        "Node Net out Rate in Mib",
-				ConstLabels: labels,
-			},
+		CfgGwRate: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "node_gateway_ratemb"),
+			"Node Rate allowed to each transfer of the transfer gateway in MB/s",
 			[]string{"node"},
+			labels,
 		),
 	}
 }
 
-func (o *NodeCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *NodeCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.Nofs,
 		o.SumStatStatfsFree,
 		o.SumStatStatfsUsed,
@@ -164,83 +173,126 @@ func (o *NodeCollector) collectorList() []prometheus.Collector {
 		o.CfgStatSysThreads,
 		o.SumStatNetInratemib,
 		o.SumStatNetOutratemib,
+		o.Status,
+		o.HeartbeatAge,
+		o.CfgStatus,
+		o.Info,
+		o.CfgGwNtx,
+		o.CfgGwRate,
 	}
 }
 
-func (o *NodeCollector) collectNodeDF() error {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
+func (o *NodeCollector) collectNodeDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	mds, err := client.ListNode(context.Background(), "root")
+	mds, err := client.ListNode(ctx)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	for _, m := range mds {
 
 		nofs, err := strconv.ParseFloat(m.Nofs, 64)
 		if err == nil {
-			o.Nofs.WithLabelValues(m.Hostport).Set(nofs)
+			ch <- prometheus.MustNewConstMetric(o.Nofs, prometheus.GaugeValue, nofs, m.Hostport)
 		}
 
 		fbytes, err := strconv.ParseFloat(m.SumStatStatfsFree, 64)
 		if err == nil {
-			o.SumStatStatfsFree.WithLabelValues(m.Hostport).Set(fbytes)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFree, prometheus.GaugeValue, fbytes, m.Hostport)
 		}
 
 		ubytes, err := strconv.ParseFloat(m.SumStatStatfsUsed, 64)
 		if err == nil {
-			o.SumStatStatfsUsed.WithLabelValues(m.Hostport).Set(ubytes)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsUsed, prometheus.GaugeValue, ubytes, m.Hostport)
 		}
 
 		tbytes, err := strconv.ParseFloat(m.SumStatStatfsTotal, 64)
 		if err == nil {
-			o.SumStatStatfsTotal.WithLabelValues(m.Hostport).Set(tbytes)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsTotal, prometheus.GaugeValue, tbytes, m.Hostport)
 		}
 
 		ffiles, err := strconv.ParseFloat(m.SumStatStatFilesFree, 64)
 		if err == nil {
-			o.SumStatStatFilesFree.WithLabelValues(m.Hostport).Set(ffiles)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatFilesFree, prometheus.GaugeValue, ffiles, m.Hostport)
 		}
 
 		ufiles, err := strconv.ParseFloat(m.SumStatStatFilesUsed, 64)
 		if err == nil {
-			o.SumStatStatFilesUsed.WithLabelValues(m.Hostport).Set(ufiles)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatFilesUsed, prometheus.GaugeValue, ufiles, m.Hostport)
 		}
 
 		tfiles, err := strconv.ParseFloat(m.SumStatStatFilesTotal, 64)
 		if err == nil {
-			o.SumStatStatFilesTotal.WithLabelValues(m.Hostport).Set(tfiles)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatFilesTotal, prometheus.GaugeValue, tfiles, m.Hostport)
 		}
 
 		ropen, err := strconv.ParseFloat(m.SumStatRopen, 64)
 		if err == nil {
-			o.SumStatRopen.WithLabelValues(m.Hostport).Set(ropen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatRopen, prometheus.GaugeValue, ropen, m.Hostport)
 		}
 
 		wopen, err := strconv.ParseFloat(m.SumStatWopen, 64)
 		if err == nil {
-			o.SumStatWopen.WithLabelValues(m.Hostport).Set(wopen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatWopen, prometheus.GaugeValue, wopen, m.Hostport)
 		}
 
 		netin, err := strconv.ParseFloat(m.SumStatNetInratemib, 64)
 		if err == nil {
-			o.SumStatNetInratemib.WithLabelValues(m.Hostport).Set(netin)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetInratemib, prometheus.GaugeValue, netin, m.Hostport)
 		}
 
 		netout, err := strconv.ParseFloat(m.SumStatNetOutratemib, 64)
 		if err == nil {
-			o.SumStatNetOutratemib.WithLabelValues(m.Hostport).Set(netout)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetOutratemib, prometheus.GaugeValue, netout, m.Hostport)
 		}
 
 		threads, err := strconv.ParseFloat(m.CfgStatSysThreads, 64)
 		if err == nil {
-			o.CfgStatSysThreads.WithLabelValues(m.Hostport).Set(threads)
+			ch <- prometheus.MustNewConstMetric(o.CfgStatSysThreads, prometheus.GaugeValue, threads, m.Hostport)
+		}
+
+		// Status
+
+		status := m.Status
+		if status != "online" && status != "offline" {
+			status = "unknown"
+		}
+		for _, s := range nodeStatuses {
+			value := 0.0
+			if s == status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(o.Status, prometheus.GaugeValue, value, m.Hostport, s)
+		}
+
+		heartbeat, err := strconv.ParseFloat(m.HeartbeatDelta, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.HeartbeatAge, prometheus.GaugeValue, heartbeat, m.Hostport)
+		}
+
+		cfg_status := 0
+		switch m.CfgStatus {
+		case "on":
+			cfg_status = 1
+		default:
+			cfg_status = 0
+		}
+		ch <- prometheus.MustNewConstMetric(o.CfgStatus, prometheus.GaugeValue, float64(cfg_status), m.Hostport)
+
+		ch <- prometheus.MustNewConstMetric(o.Info, prometheus.GaugeValue, 1, m.Hostport, m.CfgStatGeotag, m.CfgTxgw)
+
+		ntx, err := strconv.ParseFloat(m.CfgGwNtx, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.CfgGwNtx, prometheus.GaugeValue, ntx, m.Hostport)
+		}
+
+		rate, err := strconv.ParseFloat(m.CfgGwRate, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.CfgGwRate, prometheus.GaugeValue, rate, m.Hostport)
 		}
 	}
 
@@ -250,19 +302,12 @@ func (o *NodeCollector) collectNodeDF() error {
 
 // Describe sends the descriptors of each NodeCollector related metrics we have defined
 func (o *NodeCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *NodeCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectNodeDF(); err != nil {
-		log.Println("failed collecting node metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *NodeCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectNodeDF(ctx, ch)
 }
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestNodeCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"node ls -m": {Stdout: "type=nodesview hostport=fst1.example.org:1095 status=online nofs=2 sum.stat.statfs.freebytes=100 sum.stat.statfs.usedbytes=50 sum.stat.statfs.capacity=150 sum.stat.statfs.ffree=10 sum.stat.usedfiles=5 sum.stat.statfs.files=15 sum.stat.ropen=1 sum.stat.wopen=2 cfg.stat.sys.threads=100 sum.stat.net.inratemib=1.5 sum.stat.net.outratemib=2.5\n" +
+			"type=nodesview hostport=fst2.example.org:1096 status=offline nofs=1\n"},
+	})
+
+	got := collect(t, NewNodeCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_node_nofs{cluster="test",node="fst1.example.org:1095"}`:              2,
+		`eos_node_nofs{cluster="test",node="fst2.example.org:1096"}`:              1,
+		`eos_node_statfs_freebytes{cluster="test",node="fst1.example.org:1095"}`:  100,
+		`eos_node_statfs_usedbytes{cluster="test",node="fst1.example.org:1095"}`:  50,
+		`eos_node_statfs_sizebytes{cluster="test",node="fst1.example.org:1095"}`:  150,
+		`eos_node_statfs_freefiles{cluster="test",node="fst1.example.org:1095"}`:  10,
+		`eos_node_statfs_usedfiles{cluster="test",node="fst1.example.org:1095"}`:  5,
+		`eos_node_statfs_totalfiles{cluster="test",node="fst1.example.org:1095"}`: 15,
+		`eos_node_disk_ropen{cluster="test",node="fst1.example.org:1095"}`:        1,
+		`eos_node_disk_wopen{cluster="test",node="fst1.example.org:1095"}`:        2,
+		`eos_node_threads{cluster="test",node="fst1.example.org:1095"}`:           100,
+		`eos_node_net_inratemib{cluster="test",node="fst1.example.org:1095"}`:     1.5,
+		`eos_node_net_outratemib{cluster="test",node="fst1.example.org:1095"}`:    2.5,
+	})
+	checkAbsent(t, got, `eos_node_statfs_freebytes{cluster="test",node="fst2.example.org:1096"}`)
+}
+
+func TestNodeCollectorStatus(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"node ls -m": {Stdout: "type=nodesview hostport=fst1.example.org:1095 status=online heartbeatdelta=2 cfg.status=on cfg.stat.geotag=cern::0513 cfg.txgw=on cfg.gw.ntx=10 cfg.gw.rate=120\n" +
+			"type=nodesview hostport=fst2.example.org:1096 status=offline heartbeatdelta=600 cfg.status=off cfg.stat.geotag=cern::0513 cfg.txgw=off\n" +
+			"type=nodesview hostport=fst3.example.org:1095 status=booting\n"},
+	})
+
+	got := collect(t, NewNodeCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_node_status{cluster="test",node="fst1.example.org:1095",status="online"}`:              1,
+		`eos_node_status{cluster="test",node="fst1.example.org:1095",status="offline"}`:             0,
+		`eos_node_status{cluster="test",node="fst1.example.org:1095",status="unknown"}`:             0,
+		`eos_node_status{cluster="test",node="fst2.example.org:1096",status="offline"}`:             1,
+		`eos_node_status{cluster="test",node="fst3.example.org:1095",status="unknown"}`:             1,
+		`eos_node_status{cluster="test",node="fst3.example.org:1095",status="online"}`:              0,
+		`eos_node_heartbeat_age_seconds{cluster="test",node="fst1.example.org:1095"}`:               2,
+		`eos_node_heartbeat_age_seconds{cluster="test",node="fst2.example.org:1096"}`:               600,
+		`eos_node_config_status{cluster="test",node="fst1.example.org:1095"}`:                       1,
+		`eos_node_config_status{cluster="test",node="fst2.example.org:1096"}`:                       0,
+		`eos_node_info{cluster="test",geotag="cern::0513",node="fst1.example.org:1095",txgw="on"}`:  1,
+		`eos_node_info{cluster="test",geotag="cern::0513",node="fst2.example.org:1096",txgw="off"}`: 1,
+		`eos_node_gateway_ntx{cluster="test",node="fst1.example.org:1095"}`:                         10,
+		`eos_node_gateway_ratemb{cluster="test",node="fst1.example.org:1095"}`:                      120,
+	})
+	// a node without gateway settings or heartbeat does not export them
+	checkAbsent(t, got,
+		`eos_node_gateway_ntx{cluster="test",node="fst2.example.org:1096"}`,
+		`eos_node_heartbeat_age_seconds{cluster="test",node="fst3.example.org:1095"}`,
+	)
+}
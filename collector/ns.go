@@ -2,84 +2,98 @@ package collector
 
 import (
 	"context"
-	"log"
+	"sort"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
-
 	//"os"
 	//"bufio"
-	"fmt"
+	//"fmt"
 	//"strings"
 )
 
 type NSCollector struct {
-	Boot_file_time                             *prometheus.GaugeVec
-	Boot_status                                *prometheus.GaugeVec
-	Boot_time                                  *prometheus.GaugeVec
-	Cache_container_maxsize                    *prometheus.GaugeVec
-	Cache_container_occupancy                  *prometheus.GaugeVec
-	Cache_files_maxsize                        *prometheus.GaugeVec
-	Cache_files_occupancy                      *prometheus.GaugeVec
-	Fds_all                                    *prometheus.GaugeVec
-	Fusex_activeclients                        *prometheus.GaugeVec
-	Fusex_caps                                 *prometheus.GaugeVec
-	Fusex_clients                              *prometheus.GaugeVec
-	Fusex_lockedclients                        *prometheus.GaugeVec
-	Latency_dirs                               *prometheus.GaugeVec
-	Latency_files                              *prometheus.GaugeVec
-	Latency_pending_updates                    *prometheus.GaugeVec
-	Latencypeak_eosviewmutex_1min              *prometheus.GaugeVec
-	Latencypeak_eosviewmutex_2min              *prometheus.GaugeVec
-	Latencypeak_eosviewmutex_5min              *prometheus.GaugeVec
-	Latencypeak_eosviewmutex_last              *prometheus.GaugeVec
-	Memory_growth                              *prometheus.GaugeVec
-	Memory_resident                            *prometheus.GaugeVec
-	Memory_share                               *prometheus.GaugeVec
-	Memory_virtual                             *prometheus.GaugeVec
-	Stat_threads                               *prometheus.GaugeVec
-	Total_directories                          *prometheus.GaugeVec
-	Total_directories_changelog_avg_entry_size *prometheus.GaugeVec
-	Total_directories_changelog_size           *prometheus.GaugeVec
-	Total_files                                *prometheus.GaugeVec
-	Total_files_changelog_avg_entry_size       *prometheus.GaugeVec
-	Total_files_changelog_size                 *prometheus.GaugeVec
-	Uptime                                     *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	Boot_file_time                             *prometheus.Desc
+	Boot_status                                *prometheus.Desc
+	Boot_time                                  *prometheus.Desc
+	Cache_container_maxsize                    *prometheus.Desc
+	Cache_container_occupancy                  *prometheus.Desc
+	Cache_files_maxsize                        *prometheus.Desc
+	Cache_files_occupancy                      *prometheus.Desc
+	Fds_all                                    *prometheus.Desc
+	Fusex_activeclients                        *prometheus.Desc
+	Fusex_caps                                 *prometheus.Desc
+	Fusex_clients                              *prometheus.Desc
+	Fusex_lockedclients                        *prometheus.Desc
+	Latency_dirs                               *prometheus.Desc
+	Latency_files                              *prometheus.Desc
+	Latency_pending_updates                    *prometheus.Desc
+	Latencypeak_eosviewmutex_1min              *prometheus.Desc
+	Latencypeak_eosviewmutex_2min              *prometheus.Desc
+	Latencypeak_eosviewmutex_5min              *prometheus.Desc
+	Latencypeak_eosviewmutex_last              *prometheus.Desc
+	Memory_growth                              *prometheus.Desc
+	Memory_resident                            *prometheus.Desc
+	Memory_share                               *prometheus.Desc
+	Memory_virtual                             *prometheus.Desc
+	Stat_threads                               *prometheus.Desc
+	Total_directories                          *prometheus.Desc
+	Total_directories_changelog_avg_entry_size *prometheus.Desc
+	Total_directories_changelog_size           *prometheus.Desc
+	Total_files                                *prometheus.Desc
+	Total_files_changelog_avg_entry_size       *prometheus.Desc
+	Total_files_changelog_size                 *prometheus.Desc
+	Uptime                                     *prometheus.Desc
 }
 
 type NSActivityCollector struct {
-	Sum        *prometheus.GaugeVec
-	Last_5s    *prometheus.GaugeVec
-	Last_60s   *prometheus.GaugeVec
-	Last_300s  *prometheus.GaugeVec
-	Last_3600s *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	// topUsers is the number of uids, and of gids, whose activity is exported on its own.
+	// The activity of the others is folded into "other". None is exported when 0.
+	topUsers int
+
+	Sum        *prometheus.Desc
+	Last_5s    *prometheus.Desc
+	Last_60s   *prometheus.Desc
+	Last_300s  *prometheus.Desc
+	Last_3600s *prometheus.Desc
+	Exec       *prometheus.Desc
+	Sigma      *prometheus.Desc
+	Exec99     *prometheus.Desc
+	Max        *prometheus.Desc
+
+	Uid_sum        *prometheus.Desc
+	Uid_last_5s    *prometheus.Desc
+	Uid_last_60s   *prometheus.Desc
+	Uid_last_300s  *prometheus.Desc
+	Uid_last_3600s *prometheus.Desc
+	Gid_sum        *prometheus.Desc
+	Gid_last_5s    *prometheus.Desc
+	Gid_last_60s   *prometheus.Desc
+	Gid_last_300s  *prometheus.Desc
+	Gid_last_3600s *prometheus.Desc
 }
 
-var Mds []*eosclient.NSInfo
-var Mdsact []*eosclient.NSActivityInfo
-var err error
-
-func init() {
-	Mds, Mdsact, err = getNSData()
-	fmt.Println("Data initialized")
-}
+// msPerSecond converts the execution times of the NS activity, given in milliseconds.
+const msPerSecond = 1000
 
 //NewNSCollector creates an instance of the NSCollector and instantiates
 // the individual metrics that show information about the NS.
-func NewNSCollector(cluster string) *NSCollector {
+func NewNSCollector(cluster string, opt *eosclient.Options) *NSCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &NSCollector{
-		Boot_file_time: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_boot_file_time_seconds",
-				Help:        "Boot_file_time: TODO.",
-				ConstLabels: labels,
-			},
+		opt: opt,
+		Boot_file_time: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_boot_file_time_seconds"),
+			"Boot_file_time: TODO.",
 			[]string{},
+			labels,
 		),
 		//Boot_status: prometheus.NewGaugeVec(
 		//	prometheus.GaugeOpts{
@@ -90,327 +104,312 @@ func NewNSCollector(cluster string) *NSCollector {
 		//	},
 		//	[]string{},
 		//),
-		Boot_time: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_boot_time_seconds",
-				Help:        "Boot_time: Time to perform the last boot.",
-				ConstLabels: labels,
-			},
+		Boot_time: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_boot_time_seconds"),
+			"Boot_time: Time to perform the last boot.",
 			[]string{},
+			labels,
 		),
-		Cache_container_maxsize: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_cache_container_max_total",
-				Help:        "Cache_container_maxsize: Max number of containers allowed in this namespace.",
-				ConstLabels: labels,
-			},
+		Cache_container_maxsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_cache_container_max_total"),
+			"Cache_container_maxsize: Max number of containers allowed in this namespace.",
 			[]string{},
+			labels,
 		),
-		Cache_container_occupancy: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_cache_container_occ_total",
-				Help:        "Cache_container_occupancy: Total number of containers occupied in cache.",
-				ConstLabels: labels,
-			},
+		Cache_container_occupancy: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_cache_container_occ_total"),
+			"Cache_container_occupancy: Total number of containers occupied in cache.",
 			[]string{},
+			labels,
 		),
-		Cache_files_maxsize: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_cache_files_total",
-				Help:        "Cache_files_maxsize: Number of max cache files.",
-				ConstLabels: labels,
-			},
+		Cache_files_maxsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_cache_files_total"),
+			"Cache_files_maxsize: Number of max cache files.",
 			[]string{},
+			labels,
 		),
-		Cache_files_occupancy: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_cache_files_occ_total",
-				Help:        "Cache_files_occupancy: Number of cache files occupied.",
-				ConstLabels: labels,
-			},
+		Cache_files_occupancy: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_cache_files_occ_total"),
+			"Cache_files_occupancy: Number of cache files occupied.",
 			[]string{},
+			labels,
 		),
-		Fds_all: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_fds_total",
-				Help:        "Fds_all: TODO.",
-				ConstLabels: labels,
-			},
+		Fds_all: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_fds_total"),
+			"Fds_all: TODO.",
 			[]string{},
+			labels,
 		),
-		Fusex_activeclients: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_fusex_activeclients_total",
-				Help:        "Fusex_clients: Active FUSEX clients.",
-				ConstLabels: labels,
-			},
+		Fusex_activeclients: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_fusex_activeclients_total"),
+			"Fusex_clients: Active FUSEX clients.",
 			[]string{},
+			labels,
 		),
-		Fusex_caps: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_fusex_caps_total",
-				Help:        "Fusex_caps: Current FUSEX caps performed.",
-				ConstLabels: labels,
-			},
+		Fusex_caps: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_fusex_caps_total"),
+			"Fusex_caps: Current FUSEX caps performed.",
 			[]string{},
+			labels,
 		),
-		Fusex_clients: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_fusex_clients_total",
-				Help:        "Fusex_clients: Total FUSEX clients.",
-				ConstLabels: labels,
-			},
+		Fusex_clients: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_fusex_clients_total"),
+			"Fusex_clients: Total FUSEX clients.",
 			[]string{},
+			labels,
 		),
-		Fusex_lockedclients: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_fusex_locked_clients_total",
-				Help:        "Fusex_lockedclients: Locked FUSEX clients.",
-				ConstLabels: labels,
-			},
+		Fusex_lockedclients: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_fusex_locked_clients_total"),
+			"Fusex_lockedclients: Locked FUSEX clients.",
 			[]string{},
+			labels,
 		),
-		Latency_dirs: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_dirs_seconds",
-				Help:        "Latency_dirs: Directory latency in seconds.",
-				ConstLabels: labels,
-			},
+		Latency_dirs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_dirs_seconds"),
+			"Latency_dirs: Directory latency in seconds.",
 			[]string{},
+			labels,
 		),
-		Latency_files: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_files_seconds",
-				Help:        "Latency_files: Files' latency in seconds.",
-				ConstLabels: labels,
-			},
+		Latency_files: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_files_seconds"),
+			"Latency_files: Files' latency in seconds.",
 			[]string{},
+			labels,
 		),
-		Latency_pending_updates: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_pend_upd_seconds",
-				Help:        "Latency_pending_updates:  Latency of pending updates is seconds.",
-				ConstLabels: labels,
-			},
+		Latency_pending_updates: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_pend_upd_seconds"),
+			"Latency_pending_updates:  Latency of pending updates is seconds.",
 			[]string{},
+			labels,
 		),
-		Latencypeak_eosviewmutex_1min: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_eosvm_1min_seconds",
-				Help:        "Latencypeak_eosviewmutex_1min: TODO.",
-				ConstLabels: labels,
-			},
+		Latencypeak_eosviewmutex_1min: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_eosvm_1min_seconds"),
+			"Latencypeak_eosviewmutex_1min: TODO.",
 			[]string{},
+			labels,
 		),
-		Latencypeak_eosviewmutex_2min: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_eosvm_2min_seconds",
-				Help:        "Latencypeak_eosviewmutex_2min: TODO.",
-				ConstLabels: labels,
-			},
+		Latencypeak_eosviewmutex_2min: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_eosvm_2min_seconds"),
+			"Latencypeak_eosviewmutex_2min: TODO.",
 			[]string{},
+			labels,
 		),
-		Latencypeak_eosviewmutex_5min: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_eosvm_5min_seconds",
-				Help:        "Latencypeak_eosviewmutex_5min: TODO.",
-				ConstLabels: labels,
-			},
+		Latencypeak_eosviewmutex_5min: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_eosvm_5min_seconds"),
+			"Latencypeak_eosviewmutex_5min: TODO.",
 			[]string{},
+			labels,
 		),
-		Latencypeak_eosviewmutex_last: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_lat_eosvm_last_seconds",
-				Help:        "Latencypeak_eosviewmutex_last: TODO.",
-				ConstLabels: labels,
-			},
+		Latencypeak_eosviewmutex_last: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_lat_eosvm_last_seconds"),
+			"Latencypeak_eosviewmutex_last: TODO.",
 			[]string{},
+			labels,
 		),
-		Memory_growth: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_mem_growth_bytes",
-				Help:        "Memory_growth: TODO in bytes.",
-				ConstLabels: labels,
-			},
+		Memory_growth: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_mem_growth_bytes"),
+			"Memory_growth: TODO in bytes.",
 			[]string{},
+			labels,
 		),
-		Memory_resident: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_mem_res_bytes",
-				Help:        "Memory_resident: Resident memory size in bytes.",
-				ConstLabels: labels,
-			},
+		Memory_resident: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_mem_res_bytes"),
+			"Memory_resident: Resident memory size in bytes.",
 			[]string{},
+			labels,
 		),
-		Memory_share: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_mem_share_bytes",
-				Help:        "Memory_share: Shared memory size in bytes.",
-				ConstLabels: labels,
-			},
+		Memory_share: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_mem_share_bytes"),
+			"Memory_share: Shared memory size in bytes.",
 			[]string{},
+			labels,
 		),
-		Memory_virtual: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_mem_virt_bytes",
-				Help:        "Memory_virtual: Virtual memory size in bytes.",
-				ConstLabels: labels,
-			},
+		Memory_virtual: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_mem_virt_bytes"),
+			"Memory_virtual: Virtual memory size in bytes.",
 			[]string{},
+			labels,
 		),
-		Stat_threads: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_threads_total",
-				Help:        "Stat_threads: Number of used threads.",
-				ConstLabels: labels,
-			},
+		Stat_threads: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_threads_total"),
+			"Stat_threads: Number of used threads.",
 			[]string{},
+			labels,
 		),
-		Total_directories: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_dirs_total",
-				Help:        "Total_directories: Number of directories present in this namespace.",
-				ConstLabels: labels,
-			},
+		Total_directories: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_dirs_total"),
+			"Total_directories: Number of directories present in this namespace.",
 			[]string{},
+			labels,
 		),
-		Total_directories_changelog_avg_entry_size: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_dirs_clog_avg_entry_size_total",
-				Help:        "Total_directories_changelog_avg_entry_size: TODO",
-				ConstLabels: labels,
-			},
+		Total_directories_changelog_avg_entry_size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_dirs_clog_avg_entry_size_total"),
+			"Total_directories_changelog_avg_entry_size: TODO",
 			[]string{},
+			labels,
 		),
-		Total_directories_changelog_size: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_dirs_clog_size_total",
-				Help:        "Total_directories_changelog_size: TODO",
-				ConstLabels: labels,
-			},
+		Total_directories_changelog_size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_dirs_clog_size_total"),
+			"Total_directories_changelog_size: TODO",
 			[]string{},
+			labels,
 		),
-		Total_files: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_files_total",
-				Help:        "Total_files: Total files residing in the namespace.",
-				ConstLabels: labels,
-			},
+		Total_files: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_files_total"),
+			"Total_files: Total files residing in the namespace.",
 			[]string{},
+			labels,
 		),
-		Total_files_changelog_avg_entry_size: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_files_clog_avg_entry_size_total",
-				Help:        "Total_files_changelog_avg_entry_size: TODO",
-				ConstLabels: labels,
-			},
+		Total_files_changelog_avg_entry_size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_files_clog_avg_entry_size_total"),
+			"Total_files_changelog_avg_entry_size: TODO",
 			[]string{},
+			labels,
 		),
-		Total_files_changelog_size: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_files_clog_size_total",
-				Help:        "Total_files_changelog_size: TODO",
-				ConstLabels: labels,
-			},
+		Total_files_changelog_size: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_files_clog_size_total"),
+			"Total_files_changelog_size: TODO",
 			[]string{},
+			labels,
 		),
-		Uptime: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_uptime_seconds",
-				Help:        "Uptime: Time since the namespace was started last time in seconds.",
-				ConstLabels: labels,
-			},
+		Uptime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_uptime_seconds"),
+			"Uptime: Time since the namespace was started last time in seconds.",
 			[]string{},
+			labels,
 		),
 	}
 }
 
 //NewNSActivityCollector creates an instance of the NSActivityCollector and instantiates
 // the individual metrics that show information about the NS activity.
-func NewNSActivityCollector(cluster string) *NSActivityCollector {
+// The activity of the topUsers busiest uids and gids is exported too.
+func NewNSActivityCollector(cluster string, opt *eosclient.Options, topUsers int) *NSActivityCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &NSActivityCollector{
-		Sum: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_stat_sum_total",
-				Help:        "Sum: Cummulated ocurrences of the operation.",
-				ConstLabels: labels,
-			},
+		opt:      opt,
+		topUsers: topUsers,
+		Sum: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_sum_total"),
+			"Sum: Cummulated ocurrences of the operation.",
+			[]string{"user", "operation"},
+			labels,
+		),
+		Last_5s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_last5s"),
+			"Last_5s: Cummulated ocurrences of the operation in the last 5s.",
+			[]string{"user", "operation"},
+			labels,
+		),
+		Last_60s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_last1min"),
+			"Last_60s: Cummulated ocurrences of the operation in the last minute.",
+			[]string{"user", "operation"},
+			labels,
+		),
+		Last_300s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_last5min"),
+			"Last_300s: Cummulated ocurrences of the operation in the last 5 min.",
 			[]string{"user", "operation"},
+			labels,
 		),
-		Last_5s: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_stat_last5s",
-				Help:        "Last_5s: Cummulated ocurrences of the operation in the last 5s.",
-				ConstLabels: labels,
-			},
+		Last_3600s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_last1h"),
+			"Last_3600s: Cummulated ocurrences of the operation in the last hour.",
 			[]string{"user", "operation"},
+			labels,
 		),
-		Last_60s: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_stat_last1min",
-				Help:        "Last_60s: Cummulated ocurrences of the operation in the last minute.",
-				ConstLabels: labels,
-			},
+		Exec: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_exec_seconds"),
+			"Exec: Average execution time of the operation.",
 			[]string{"user", "operation"},
+			labels,
 		),
-		Last_300s: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_stat_last5min",
-				Help:        "Last_300s: Cummulated ocurrences of the operation in the last 5 min.",
-				ConstLabels: labels,
-			},
+		Sigma: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_exec_sigma_seconds"),
+			"Sigma: Standard deviation of the execution time of the operation.",
 			[]string{"user", "operation"},
+			labels,
 		),
-		Last_3600s: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "ns_stat_last1h",
-				Help:        "Last_3600s: Cummulated ocurrences of the operation in the last hour.",
-				ConstLabels: labels,
-			},
+		Exec99: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_exec99_seconds"),
+			"Exec99: 99th percentile of the execution time of the operation.",
 			[]string{"user", "operation"},
+			labels,
+		),
+		Max: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_exec_max_seconds"),
+			"Max: Maximum execution time of the operation.",
+			[]string{"user", "operation"},
+			labels,
+		),
+		Uid_sum: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_uid_sum_total"),
+			"Sum: Cummulated ocurrences of the operation, by uid.",
+			[]string{"uid", "operation"},
+			labels,
+		),
+		Uid_last_5s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_uid_last5s"),
+			"Last_5s: Cummulated ocurrences of the operation in the last 5s, by uid.",
+			[]string{"uid", "operation"},
+			labels,
+		),
+		Uid_last_60s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_uid_last1min"),
+			"Last_60s: Cummulated ocurrences of the operation in the last minute, by uid.",
+			[]string{"uid", "operation"},
+			labels,
+		),
+		Uid_last_300s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_uid_last5min"),
+			"Last_300s: Cummulated ocurrences of the operation in the last 5 min, by uid.",
+			[]string{"uid", "operation"},
+			labels,
+		),
+		Uid_last_3600s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_uid_last1h"),
+			"Last_3600s: Cummulated ocurrences of the operation in the last hour, by uid.",
+			[]string{"uid", "operation"},
+			labels,
+		),
+		Gid_sum: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_gid_sum_total"),
+			"Sum: Cummulated ocurrences of the operation, by gid.",
+			[]string{"gid", "operation"},
+			labels,
+		),
+		Gid_last_5s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_gid_last5s"),
+			"Last_5s: Cummulated ocurrences of the operation in the last 5s, by gid.",
+			[]string{"gid", "operation"},
+			labels,
+		),
+		Gid_last_60s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_gid_last1min"),
+			"Last_60s: Cummulated ocurrences of the operation in the last minute, by gid.",
+			[]string{"gid", "operation"},
+			labels,
+		),
+		Gid_last_300s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_gid_last5min"),
+			"Last_300s: Cummulated ocurrences of the operation in the last 5 min, by gid.",
+			[]string{"gid", "operation"},
+			labels,
+		),
+		Gid_last_3600s: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ns_stat_gid_last1h"),
+			"Last_3600s: Cummulated ocurrences of the operation in the last hour, by gid.",
+			[]string{"gid", "operation"},
+			labels,
 		),
 	}
 }
 
-func (o *NSCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *NSCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.Boot_file_time,
 		//o.Boot_status,
 		o.Boot_time,
@@ -445,44 +444,141 @@ func (o *NSCollector) collectorList() []prometheus.Collector {
 	}
 }
 
-func (o *NSActivityCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *NSActivityCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.Sum,
 		o.Last_5s,
 		o.Last_60s,
 		o.Last_300s,
 		o.Last_3600s,
+		o.Exec,
+		o.Sigma,
+		o.Exec99,
+		o.Max,
+		o.Uid_sum,
+		o.Uid_last_5s,
+		o.Uid_last_60s,
+		o.Uid_last_300s,
+		o.Uid_last_3600s,
+		o.Gid_sum,
+		o.Gid_last_5s,
+		o.Gid_last_60s,
+		o.Gid_last_300s,
+		o.Gid_last_3600s,
 	}
 }
 
-func getNSData() ([]*eosclient.NSInfo, []*eosclient.NSActivityInfo, error) {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
-	if err != nil {
-		panic(err)
+// nsActivityValues parses the sum and the rates of an operation, in this order.
+func nsActivityValues(n *eosclient.NSActivityInfo) ([5]float64, error) {
+	var values [5]float64
+	for i, v := range []string{n.Sum, n.Last_5s, n.Last_60s, n.Last_300s, n.Last_3600s} {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return values, err
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+// topNSActivity sums the activity of each id, given by key, per operation, for the lines broken down by id.
+// Only the top ids by rate over the last minute are kept, the activity of the others is summed into "other".
+func topNSActivity(mdsact []*eosclient.NSActivityInfo, key func(*eosclient.NSActivityInfo) string, top int) map[[2]string][5]float64 {
+	activity := make(map[[2]string][5]float64)
+	rates := make(map[string]float64)
+	for _, n := range mdsact {
+		if key(n) == "all" {
+			continue
+		}
+		values, err := nsActivityValues(n)
+		if err != nil {
+			continue
+		}
+		k := [2]string{key(n), n.Operation}
+		sum := activity[k]
+		for i := range values {
+			sum[i] += values[i]
+		}
+		activity[k] = sum
+		rates[key(n)] += values[2]
 	}
 
-	mds, mdsact, err := client.ListNS(context.Background())
+	ids := make([]string, 0, len(rates))
+	for id := range rates {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if rates[ids[i]] != rates[ids[j]] {
+			return rates[ids[i]] > rates[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) <= top {
+		return activity
+	}
+
+	folded := make(map[string]bool)
+	for _, id := range ids[top:] {
+		folded[id] = true
+	}
+	for k, values := range activity {
+		if !folded[k[0]] {
+			continue
+		}
+		other := [2]string{"other", k[1]}
+		sum := activity[other]
+		for i := range values {
+			sum[i] += values[i]
+		}
+		activity[other] = sum
+		delete(activity, k)
+	}
+	return activity
+}
+
+// nsData is the outcome of "eos ns stat", shared by the NSCollector and the NSActivityCollector.
+type nsData struct {
+	mds    []*eosclient.NSInfo
+	mdsact []*eosclient.NSActivityInfo
+}
+
+func getNSData(ctx context.Context, opt *eosclient.Options) ([]*eosclient.NSInfo, []*eosclient.NSActivityInfo, error) {
+	data, err := shareListing(ctx, "ns stat", func() (interface{}, error) {
+		client, err := newEOSClient(opt)
+		if err != nil {
+			return nil, err
+		}
+
+		mds, mdsact, err := client.ListNS(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &nsData{mds, mdsact}, nil
+	})
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
-	return mds, mdsact, nil
+	ns := data.(*nsData)
+	return ns.mds, ns.mdsact, nil
 
 }
 
-func (o *NSCollector) collectNSDF() error {
+func (o *NSCollector) collectNSDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	mds, _, err := getNSData(ctx, o.opt)
+	if err != nil {
+		return err
+	}
 
 	//var boot_status float64
-	for _, m := range Mds {
+	for _, m := range mds {
 
 		// Boot_file_time
 
 		boot_ft, err := strconv.ParseFloat(m.Boot_file_time, 64)
 		if err == nil {
-			o.Boot_file_time.WithLabelValues().Set(boot_ft)
+			ch <- prometheus.MustNewConstMetric(o.Boot_file_time, prometheus.GaugeValue, boot_ft)
 		}
 
 		//// Boot_status
@@ -500,202 +596,202 @@ func (o *NSCollector) collectNSDF() error {
 
 		boot_time, err := strconv.ParseFloat(m.Boot_time, 64)
 		if err == nil {
-			o.Boot_time.WithLabelValues().Set(boot_time)
+			ch <- prometheus.MustNewConstMetric(o.Boot_time, prometheus.GaugeValue, boot_time)
 		}
 
 		// Cache_container_maxsize
 
 		cache_cont_max, err := strconv.ParseFloat(m.Cache_container_maxsize, 64)
 		if err == nil {
-			o.Cache_container_maxsize.WithLabelValues().Set(cache_cont_max)
+			ch <- prometheus.MustNewConstMetric(o.Cache_container_maxsize, prometheus.GaugeValue, cache_cont_max)
 		}
 
 		// Cache_container_occupancy
 
 		cache_cont_occ, err := strconv.ParseFloat(m.Cache_container_occupancy, 64)
 		if err == nil {
-			o.Cache_container_occupancy.WithLabelValues().Set(cache_cont_occ)
+			ch <- prometheus.MustNewConstMetric(o.Cache_container_occupancy, prometheus.GaugeValue, cache_cont_occ)
 		}
 
 		// Cache_files_maxsize
 
 		cache_files_max, err := strconv.ParseFloat(m.Cache_files_maxsize, 64)
 		if err == nil {
-			o.Cache_files_maxsize.WithLabelValues().Set(cache_files_max)
+			ch <- prometheus.MustNewConstMetric(o.Cache_files_maxsize, prometheus.GaugeValue, cache_files_max)
 		}
 
 		// Cache_files_occupancy
 
 		cache_files_occ, err := strconv.ParseFloat(m.Cache_files_occupancy, 64)
 		if err == nil {
-			o.Cache_files_occupancy.WithLabelValues().Set(cache_files_occ)
+			ch <- prometheus.MustNewConstMetric(o.Cache_files_occupancy, prometheus.GaugeValue, cache_files_occ)
 		}
 
 		// Fds_all
 
 		fds_all, err := strconv.ParseFloat(m.Fds_all, 64)
 		if err == nil {
-			o.Fds_all.WithLabelValues().Set(fds_all)
+			ch <- prometheus.MustNewConstMetric(o.Fds_all, prometheus.GaugeValue, fds_all)
 		}
 
 		// Fusex_activeclients
 
 		fusex_actclients, err := strconv.ParseFloat(m.Fusex_activeclients, 64)
 		if err == nil {
-			o.Fusex_activeclients.WithLabelValues().Set(fusex_actclients)
+			ch <- prometheus.MustNewConstMetric(o.Fusex_activeclients, prometheus.GaugeValue, fusex_actclients)
 		}
 
 		// Fusex_caps
 
 		fusex_caps, err := strconv.ParseFloat(m.Fusex_caps, 64)
 		if err == nil {
-			o.Fusex_caps.WithLabelValues().Set(fusex_caps)
+			ch <- prometheus.MustNewConstMetric(o.Fusex_caps, prometheus.GaugeValue, fusex_caps)
 		}
 
 		// Fusex_clients
 
 		fusex_clients, err := strconv.ParseFloat(m.Fusex_clients, 64)
 		if err == nil {
-			o.Fusex_clients.WithLabelValues().Set(fusex_clients)
+			ch <- prometheus.MustNewConstMetric(o.Fusex_clients, prometheus.GaugeValue, fusex_clients)
 		}
 
 		// Fusex_lockedclients
 
 		fusex_lockedcs, err := strconv.ParseFloat(m.Fusex_lockedclients, 64)
 		if err == nil {
-			o.Fusex_lockedclients.WithLabelValues().Set(fusex_lockedcs)
+			ch <- prometheus.MustNewConstMetric(o.Fusex_lockedclients, prometheus.GaugeValue, fusex_lockedcs)
 		}
 
 		// Latency_dirs
 
 		lat_dirs, err := strconv.ParseFloat(m.Latency_dirs, 64)
 		if err == nil {
-			o.Latency_dirs.WithLabelValues().Set(lat_dirs)
+			ch <- prometheus.MustNewConstMetric(o.Latency_dirs, prometheus.GaugeValue, lat_dirs)
 		}
 
 		// Latency_files
 
 		lat_files, err := strconv.ParseFloat(m.Latency_files, 64)
 		if err == nil {
-			o.Latency_files.WithLabelValues().Set(lat_files)
+			ch <- prometheus.MustNewConstMetric(o.Latency_files, prometheus.GaugeValue, lat_files)
 		}
 
 		// Latency_pending_updates
 
 		lat_pen_upd, err := strconv.ParseFloat(m.Latency_pending_updates, 64)
 		if err == nil {
-			o.Latency_pending_updates.WithLabelValues().Set(lat_pen_upd)
+			ch <- prometheus.MustNewConstMetric(o.Latency_pending_updates, prometheus.GaugeValue, lat_pen_upd)
 		}
 
 		// Latencypeak_eosviewmutex_1min
 
 		lat_eosvm_1m, err := strconv.ParseFloat(m.Latencypeak_eosviewmutex_1min, 64)
 		if err == nil {
-			o.Latencypeak_eosviewmutex_1min.WithLabelValues().Set(lat_eosvm_1m)
+			ch <- prometheus.MustNewConstMetric(o.Latencypeak_eosviewmutex_1min, prometheus.GaugeValue, lat_eosvm_1m)
 		}
 
 		// Latencypeak_eosviewmutex_2min
 
 		lat_eosvm_2m, err := strconv.ParseFloat(m.Latencypeak_eosviewmutex_2min, 64)
 		if err == nil {
-			o.Latencypeak_eosviewmutex_2min.WithLabelValues().Set(lat_eosvm_2m)
+			ch <- prometheus.MustNewConstMetric(o.Latencypeak_eosviewmutex_2min, prometheus.GaugeValue, lat_eosvm_2m)
 		}
 
 		// Latencypeak_eosviewmutex_5min
 
 		lat_eosvm_5m, err := strconv.ParseFloat(m.Latencypeak_eosviewmutex_5min, 64)
 		if err == nil {
-			o.Latencypeak_eosviewmutex_5min.WithLabelValues().Set(lat_eosvm_5m)
+			ch <- prometheus.MustNewConstMetric(o.Latencypeak_eosviewmutex_5min, prometheus.GaugeValue, lat_eosvm_5m)
 		}
 
 		// Latencypeak_eosviewmutex_last
 
 		lat_eosvm_last, err := strconv.ParseFloat(m.Latencypeak_eosviewmutex_last, 64)
 		if err == nil {
-			o.Latencypeak_eosviewmutex_last.WithLabelValues().Set(lat_eosvm_last)
+			ch <- prometheus.MustNewConstMetric(o.Latencypeak_eosviewmutex_last, prometheus.GaugeValue, lat_eosvm_last)
 		}
 
 		// Memory_growth
 
 		mem_growth, err := strconv.ParseFloat(m.Memory_growth, 64)
 		if err == nil {
-			o.Memory_growth.WithLabelValues().Set(mem_growth)
+			ch <- prometheus.MustNewConstMetric(o.Memory_growth, prometheus.GaugeValue, mem_growth)
 		}
 
 		// Memory_resident
 
 		mem_res, err := strconv.ParseFloat(m.Memory_resident, 64)
 		if err == nil {
-			o.Memory_resident.WithLabelValues().Set(mem_res)
+			ch <- prometheus.MustNewConstMetric(o.Memory_resident, prometheus.GaugeValue, mem_res)
 		}
 
 		// Memory_share
 		mem_share, err := strconv.ParseFloat(m.Memory_share, 64)
 		if err == nil {
-			o.Memory_share.WithLabelValues().Set(mem_share)
+			ch <- prometheus.MustNewConstMetric(o.Memory_share, prometheus.GaugeValue, mem_share)
 		}
 
 		// Memory_virtual
 
 		mem_virt, err := strconv.ParseFloat(m.Memory_virtual, 64)
 		if err == nil {
-			o.Memory_virtual.WithLabelValues().Set(mem_virt)
+			ch <- prometheus.MustNewConstMetric(o.Memory_virtual, prometheus.GaugeValue, mem_virt)
 		}
 
 		// Stat_threads
 
 		stat_threads, err := strconv.ParseFloat(m.Stat_threads, 64)
 		if err == nil {
-			o.Stat_threads.WithLabelValues().Set(stat_threads)
+			ch <- prometheus.MustNewConstMetric(o.Stat_threads, prometheus.GaugeValue, stat_threads)
 		}
 
 		// Total_directories
 
 		total_dirs, err := strconv.ParseFloat(m.Total_directories, 64)
 		if err == nil {
-			o.Total_directories.WithLabelValues().Set(total_dirs)
+			ch <- prometheus.MustNewConstMetric(o.Total_directories, prometheus.GaugeValue, total_dirs)
 		}
 
 		// Total_directories_changelog_avg_entry_size
 		total_dirs_clog_avg_entry_size, err := strconv.ParseFloat(m.Total_directories_changelog_avg_entry_size, 64)
 
 		if err == nil {
-			o.Total_directories_changelog_avg_entry_size.WithLabelValues().Set(total_dirs_clog_avg_entry_size)
+			ch <- prometheus.MustNewConstMetric(o.Total_directories_changelog_avg_entry_size, prometheus.GaugeValue, total_dirs_clog_avg_entry_size)
 		}
 
 		// Total_directories_changelog_size
 
 		total_dirs_clog_size, err := strconv.ParseFloat(m.Total_directories_changelog_size, 64)
 		if err == nil {
-			o.Total_directories_changelog_size.WithLabelValues().Set(total_dirs_clog_size)
+			ch <- prometheus.MustNewConstMetric(o.Total_directories_changelog_size, prometheus.GaugeValue, total_dirs_clog_size)
 		}
 
 		// Total_files
 
 		total_files, err := strconv.ParseFloat(m.Total_files, 64)
 		if err == nil {
-			o.Total_files.WithLabelValues().Set(total_files)
+			ch <- prometheus.MustNewConstMetric(o.Total_files, prometheus.GaugeValue, total_files)
 		}
 
 		// Total_files_changelog_avg_entry_size
 
 		total_files_clog_avg_entry_size, err := strconv.ParseFloat(m.Total_files_changelog_avg_entry_size, 64)
 		if err == nil {
-			o.Total_files_changelog_avg_entry_size.WithLabelValues().Set(total_files_clog_avg_entry_size)
+			ch <- prometheus.MustNewConstMetric(o.Total_files_changelog_avg_entry_size, prometheus.GaugeValue, total_files_clog_avg_entry_size)
 		}
 
 		// Total_files_changelog_size
 
 		total_files_clog_size, err := strconv.ParseFloat(m.Total_files_changelog_size, 64)
 		if err == nil {
-			o.Total_files_changelog_size.WithLabelValues().Set(total_files_clog_size)
+			ch <- prometheus.MustNewConstMetric(o.Total_files_changelog_size, prometheus.GaugeValue, total_files_clog_size)
 		}
 
 		// Uptime
 
 		uptime, err := strconv.ParseFloat(m.Uptime, 64)
 		if err == nil {
-			o.Uptime.WithLabelValues().Set(uptime)
+			ch <- prometheus.MustNewConstMetric(o.Uptime, prometheus.GaugeValue, uptime)
 		}
 	}
 
@@ -703,44 +799,97 @@ func (o *NSCollector) collectNSDF() error {
 
 } // collectNSDF()
 
-func (o *NSActivityCollector) collectNSActivityDF() error {
+func (o *NSActivityCollector) collectNSActivityDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	_, mdsact, err := getNSData(ctx, o.opt)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range mdsact {
+		// Global activity only, the breakdown by uid and gid is folded below
+		if n.User != "all" || n.Gid != "all" {
+			continue
+		}
 
-	for _, n := range Mdsact {
 		// Sum
 
 		sum, err := strconv.ParseFloat(n.Sum, 64)
 		if err == nil {
-			o.Sum.WithLabelValues(n.User, n.Operation).Set(sum)
+			ch <- prometheus.MustNewConstMetric(o.Sum, prometheus.GaugeValue, sum, n.User, n.Operation)
 		}
 
 		// Last_5s
 
 		last_5s, err := strconv.ParseFloat(n.Last_5s, 64)
 		if err == nil {
-			o.Last_5s.WithLabelValues(n.User, n.Operation).Set(last_5s)
+			ch <- prometheus.MustNewConstMetric(o.Last_5s, prometheus.GaugeValue, last_5s, n.User, n.Operation)
 		}
 
 		// Last_60s
 
 		last_1min, err := strconv.ParseFloat(n.Last_60s, 64)
 		if err == nil {
-			o.Last_60s.WithLabelValues(n.User, n.Operation).Set(last_1min)
+			ch <- prometheus.MustNewConstMetric(o.Last_60s, prometheus.GaugeValue, last_1min, n.User, n.Operation)
 		}
 
 		// Last_300s
 
 		last_5min, err := strconv.ParseFloat(n.Last_300s, 64)
 		if err == nil {
-			o.Last_300s.WithLabelValues(n.User, n.Operation).Set(last_5min)
+			ch <- prometheus.MustNewConstMetric(o.Last_300s, prometheus.GaugeValue, last_5min, n.User, n.Operation)
 		}
 
 		// Last_3600s
 
 		last_1h, err := strconv.ParseFloat(n.Last_3600s, 64)
 		if err == nil {
-			o.Last_3600s.WithLabelValues(n.User, n.Operation).Set(last_1h)
+			ch <- prometheus.MustNewConstMetric(o.Last_3600s, prometheus.GaugeValue, last_1h, n.User, n.Operation)
 		}
 
+		// Exec
+
+		exec, err := strconv.ParseFloat(n.Exec, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.Exec, prometheus.GaugeValue, exec/msPerSecond, n.User, n.Operation)
+		}
+
+		// Sigma
+
+		sigma, err := strconv.ParseFloat(n.Sigma, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.Sigma, prometheus.GaugeValue, sigma/msPerSecond, n.User, n.Operation)
+		}
+
+		// Exec99
+
+		exec99, err := strconv.ParseFloat(n.Exec99, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.Exec99, prometheus.GaugeValue, exec99/msPerSecond, n.User, n.Operation)
+		}
+
+		// Max
+
+		max, err := strconv.ParseFloat(n.Max, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.Max, prometheus.GaugeValue, max/msPerSecond, n.User, n.Operation)
+		}
+
+	}
+
+	if o.topUsers > 0 {
+		uids := topNSActivity(mdsact, func(n *eosclient.NSActivityInfo) string { return n.User }, o.topUsers)
+		for k, values := range uids {
+			for i, desc := range []*prometheus.Desc{o.Uid_sum, o.Uid_last_5s, o.Uid_last_60s, o.Uid_last_300s, o.Uid_last_3600s} {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, values[i], k[0], k[1])
+			}
+		}
+
+		gids := topNSActivity(mdsact, func(n *eosclient.NSActivityInfo) string { return n.Gid }, o.topUsers)
+		for k, values := range gids {
+			for i, desc := range []*prometheus.Desc{o.Gid_sum, o.Gid_last_5s, o.Gid_last_60s, o.Gid_last_300s, o.Gid_last_3600s} {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, values[i], k[0], k[1])
+			}
+		}
 	}
 
 	return nil
@@ -749,40 +898,26 @@ func (o *NSActivityCollector) collectNSActivityDF() error {
 
 // Describe sends the descriptors of each NSCollector related metrics we have defined
 func (o *NSCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 	//ch <- o.ScrubbingStateDesc
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *NSCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectNSDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *NSCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectNSDF(ctx, ch)
 }
 
 // Describe sends the descriptors of each NSActivityCollector related metrics we have defined
 func (o *NSActivityCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 	//ch <- o.ScrubbingStateDesc
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *NSActivityCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectNSActivityDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *NSActivityCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectNSActivityDF(ctx, ch)
 }
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// nsListing is a canned "ns stat -a -m", with the activity of the instance and of some users and groups.
+const nsListing = "uid=all gid=all ns.total.files=100\n" +
+	"uid=all gid=all ns.total.directories=10\n" +
+	"uid=all gid=all ns.uptime=1000\n" +
+	"uid=all gid=all ns.boot.status=booted\n" +
+	"uid=all gid=all ns.memory.resident=2048\n" +
+	"uid=all gid=all ns.latency.files=0.5\n" +
+	"uid=1000 gid=all ns.total.files=60\n" +
+	"uid=all gid=all cmd=Stat total=500 5s=1.00 60s=2.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+	"uid=all gid=all cmd=Open total=50 5s=0.00 60s=0.00 300s=0.00 3600s=0.00 exec=0 execsig=0 exec99=0 execmax=0\n" +
+	"uid=1000 gid=all cmd=Stat total=300 5s=1.00 60s=5.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+	"uid=1001 gid=all cmd=Stat total=200 5s=1.00 60s=3.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+	"uid=1002 gid=all cmd=Stat total=100 5s=1.00 60s=1.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+	"uid=all gid=500 cmd=Stat total=500 5s=1.00 60s=9.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n"
+
+func TestNSCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"ns stat -a -m": {Stdout: nsListing},
+	})
+
+	got := collect(t, NewNSCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_ns_files_total{cluster="test"}`:       100,
+		`eos_ns_dirs_total{cluster="test"}`:        10,
+		`eos_ns_uptime_seconds{cluster="test"}`:    1000,
+		`eos_ns_mem_res_bytes{cluster="test"}`:     2048,
+		`eos_ns_lat_files_seconds{cluster="test"}`: 0.5,
+	})
+}
+
+func TestNSActivityCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"ns stat -a -m": {Stdout: nsListing},
+	})
+
+	got := collect(t, NewNSActivityCollector("test", opt, 0))
+	checkSeries(t, got, map[string]float64{
+		`eos_ns_stat_sum_total{cluster="test",operation="Stat",user="all"}`: 500,
+		`eos_ns_stat_last5s{cluster="test",operation="Stat",user="all"}`:    1,
+		`eos_ns_stat_last1min{cluster="test",operation="Stat",user="all"}`:  2,
+		`eos_ns_stat_last5min{cluster="test",operation="Stat",user="all"}`:  3,
+		`eos_ns_stat_last1h{cluster="test",operation="Stat",user="all"}`:    4,
+	})
+	// idle operations are left out
+	checkAbsent(t, got, `eos_ns_stat_sum_total{cluster="test",operation="Open"`)
+}
+
+func TestNSActivityLatency(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"ns stat -a -m": {Stdout: nsListing},
+	})
+
+	// eos gives the execution times in milliseconds
+	got := collect(t, NewNSActivityCollector("test", opt, 0))
+	checkSeries(t, got, map[string]float64{
+		`eos_ns_stat_exec_seconds{cluster="test",operation="Stat",user="all"}`:       0.0005,
+		`eos_ns_stat_exec_sigma_seconds{cluster="test",operation="Stat",user="all"}`: 0.0001,
+		`eos_ns_stat_exec99_seconds{cluster="test",operation="Stat",user="all"}`:     0.002,
+		`eos_ns_stat_exec_max_seconds{cluster="test",operation="Stat",user="all"}`:   0.01,
+	})
+}
+
+func TestNSActivityTopUsers(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"ns stat -a -m": {Stdout: nsListing},
+	})
+
+	tests := []struct {
+		topUsers int
+		want     map[string]float64
+		absent   []string
+	}{
+		{0, nil, []string{"eos_ns_stat_uid_", "eos_ns_stat_gid_"}},
+		// 1000 is the busiest uid over the last minute, 1001 and 1002 are folded
+		{1, map[string]float64{
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="1000"}`:  300,
+			`eos_ns_stat_uid_last1min{cluster="test",operation="Stat",uid="1000"}`:   5,
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="other"}`: 300,
+			`eos_ns_stat_uid_last1min{cluster="test",operation="Stat",uid="other"}`:  4,
+			`eos_ns_stat_gid_sum_total{cluster="test",gid="500",operation="Stat"}`:   500,
+			`eos_ns_stat_gid_last1min{cluster="test",gid="500",operation="Stat"}`:    9,
+		}, []string{
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="1001"}`,
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="all"}`,
+			`eos_ns_stat_gid_sum_total{cluster="test",gid="other"`,
+		}},
+		{10, map[string]float64{
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="1001"}`: 200,
+			`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="1002"}`: 100,
+		}, []string{`eos_ns_stat_uid_sum_total{cluster="test",operation="Stat",uid="other"}`}},
+	}
+	for _, tt := range tests {
+		got := collect(t, NewNSActivityCollector("test", opt, tt.topUsers))
+		checkSeries(t, got, tt.want)
+		checkAbsent(t, got, tt.absent...)
+		// the global activity does not include the breakdown
+		checkSeries(t, got, map[string]float64{
+			`eos_ns_stat_sum_total{cluster="test",operation="Stat",user="all"}`: 500,
+		})
+	}
+}
+
+func TestNSSharedListing(t *testing.T) {
+	runner := eosclient.NewFakeRunner(map[string]eosclient.FakeResponse{
+		"ns stat -a -m": {Stdout: nsListing},
+	})
+	opt := fakeOptions(nil)
+	opt.Runner = runner
+
+	ctx := WithSharedListings(context.Background())
+	ch := make(chan prometheus.Metric, 1000)
+	for _, c := range []Collector{NewNSCollector("test", opt), NewNSActivityCollector("test", opt, 0)} {
+		if err := c.Update(ctx, ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls := runner.Calls(); len(calls) != 1 {
+		t.Errorf("ns stat was run %d times within a scrape, want 1", len(calls))
+	}
+}
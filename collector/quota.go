@@ -0,0 +1,185 @@
+package collector
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// projectGid is the gid eos books the project quotas on.
+const projectGid = "99"
+
+type QuotaCollector struct {
+	opt *eosclient.Options
+
+	// include and exclude select the quota nodes to export, by path. nil means no restriction.
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+
+	UsedBytes        *prometheus.Desc
+	UsedLogicalBytes *prometheus.Desc
+	UsedFiles        *prometheus.Desc
+	MaxBytes         *prometheus.Desc
+	MaxLogicalBytes  *prometheus.Desc
+	MaxFiles         *prometheus.Desc
+}
+
+// NewQuotaCollector creates an instance of the QuotaCollector.
+// Only the quota nodes whose path matches include, and not exclude, are exported; empty expressions match everything.
+func NewQuotaCollector(cluster string, opt *eosclient.Options, include, exclude string) (*QuotaCollector, error) {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	quotaLabels := []string{"quotanode", "uid", "gid", "project"}
+
+	o := &QuotaCollector{
+		opt: opt,
+
+		UsedBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_used_bytes"),
+			"Quota physical bytes used, replicas and parity included",
+			quotaLabels,
+			labels,
+		),
+		UsedLogicalBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_used_logical_bytes"),
+			"Quota logical bytes used",
+			quotaLabels,
+			labels,
+		),
+		UsedFiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_used_files"),
+			"Quota number of files used",
+			quotaLabels,
+			labels,
+		),
+		MaxBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_max_bytes"),
+			"Quota physical bytes allowed, 0 if unlimited",
+			quotaLabels,
+			labels,
+		),
+		MaxLogicalBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_max_logical_bytes"),
+			"Quota logical bytes allowed, 0 if unlimited",
+			quotaLabels,
+			labels,
+		),
+		MaxFiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_max_files"),
+			"Quota number of files allowed, 0 if unlimited",
+			quotaLabels,
+			labels,
+		),
+	}
+
+	var err error
+	if include != "" {
+		if o.include, err = regexp.Compile(include); err != nil {
+			return nil, err
+		}
+	}
+	if exclude != "" {
+		if o.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *QuotaCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.UsedBytes,
+		o.UsedLogicalBytes,
+		o.UsedFiles,
+		o.MaxBytes,
+		o.MaxLogicalBytes,
+		o.MaxFiles,
+	}
+}
+
+// selected tells whether the quota node has to be exported.
+func (o *QuotaCollector) selected(quotanode string) bool {
+	if o.include != nil && !o.include.MatchString(quotanode) {
+		return false
+	}
+	return o.exclude == nil || !o.exclude.MatchString(quotanode)
+}
+
+// quotaLabelValues returns the uid, gid and project labels of a quota.
+// Project quotas are booked by eos on a dedicated gid, they are named after the quota node.
+func quotaLabelValues(m *eosclient.QuotaInfo) (string, string, string) {
+	if m.Gid == "project" || m.Gid == projectGid {
+		return "", "", path.Base(strings.TrimSuffix(m.Space, "/"))
+	}
+	return m.Uid, m.Gid, ""
+}
+
+func (o *QuotaCollector) collectQuotaDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.ListQuota(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mds {
+		if !o.selected(m.Space) {
+			continue
+		}
+		uid, gid, project := quotaLabelValues(m)
+
+		used, err := strconv.ParseFloat(m.UsedBytes, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.UsedBytes, prometheus.GaugeValue, used, m.Space, uid, gid, project)
+		}
+
+		usedl, err := strconv.ParseFloat(m.UsedLogicalBytes, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.UsedLogicalBytes, prometheus.GaugeValue, usedl, m.Space, uid, gid, project)
+		}
+
+		usedf, err := strconv.ParseFloat(m.UsedFiles, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.UsedFiles, prometheus.GaugeValue, usedf, m.Space, uid, gid, project)
+		}
+
+		max, err := strconv.ParseFloat(m.MaxBytes, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.MaxBytes, prometheus.GaugeValue, max, m.Space, uid, gid, project)
+		}
+
+		maxl, err := strconv.ParseFloat(m.MaxLogicalBytes, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.MaxLogicalBytes, prometheus.GaugeValue, maxl, m.Space, uid, gid, project)
+		}
+
+		maxf, err := strconv.ParseFloat(m.MaxFiles, 64)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(o.MaxFiles, prometheus.GaugeValue, maxf, m.Space, uid, gid, project)
+		}
+	}
+
+	return nil
+
+} // collectQuotaDF()
+
+// Describe sends the descriptors of each QuotaCollector related metrics we have defined
+func (o *QuotaCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *QuotaCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectQuotaDF(ctx, ch)
+}
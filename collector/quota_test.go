@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// quotaListing is a canned "quota ls -m", with a user, a group and a project quota.
+const quotaListing = "quota=node uid=alice space=/eos/user/ usedbytes=200 usedlogicalbytes=100 usedfiles=3 maxbytes=2000 maxlogicalbytes=1000 maxfiles=100 percentageusedbytes=10.00 statusbytes=ok statusfiles=ok\n" +
+	"quota=node gid=ops space=/eos/user/ usedbytes=20 usedlogicalbytes=10 usedfiles=1 maxbytes=0 maxlogicalbytes=0 maxfiles=0 percentageusedbytes=0.00 statusbytes=ignored statusfiles=ignored\n" +
+	"quota=node gid=99 space=/eos/project/atlas/ usedbytes=2 usedlogicalbytes=1 usedfiles=1 maxbytes=4 maxlogicalbytes=2 maxfiles=10 percentageusedbytes=50.00 statusbytes=ok statusfiles=ok\n" +
+	"quota=node uid=bob space=/eos/scratch/ usedbytes=2 usedlogicalbytes=1 usedfiles=1 maxbytes=4 maxlogicalbytes=2 maxfiles=10 percentageusedbytes=50.00 statusbytes=ok statusfiles=ok\n"
+
+func TestQuotaCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"quota ls -m": {Stdout: quotaListing},
+	})
+
+	c, err := NewQuotaCollector("test", opt, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := collect(t, c)
+	checkSeries(t, got, map[string]float64{
+		`eos_quota_used_bytes{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:                 200,
+		`eos_quota_used_logical_bytes{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:         100,
+		`eos_quota_used_files{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:                 3,
+		`eos_quota_max_bytes{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:                  2000,
+		`eos_quota_max_logical_bytes{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:          1000,
+		`eos_quota_max_files{cluster="test",gid="",project="",quotanode="/eos/user/",uid="alice"}`:                  100,
+		`eos_quota_max_bytes{cluster="test",gid="ops",project="",quotanode="/eos/user/",uid=""}`:                    0,
+		`eos_quota_used_bytes{cluster="test",gid="",project="atlas",quotanode="/eos/project/atlas/",uid=""}`:        2,
+		`eos_quota_used_bytes{cluster="test",gid="",project="",quotanode="/eos/scratch/",uid="bob"}`:                2,
+		`eos_quota_max_logical_bytes{cluster="test",gid="",project="atlas",quotanode="/eos/project/atlas/",uid=""}`: 2,
+	})
+}
+
+func TestQuotaCollectorSelection(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"quota ls -m": {Stdout: quotaListing},
+	})
+
+	tests := []struct {
+		include, exclude string
+		nodes            []string
+	}{
+		{"", "", []string{"/eos/user/", "/eos/project/atlas/", "/eos/scratch/"}},
+		{"^/eos/user/", "", []string{"/eos/user/"}},
+		{"", "^/eos/scratch/", []string{"/eos/user/", "/eos/project/atlas/"}},
+		{"^/eos/(user|scratch)/", "scratch", []string{"/eos/user/"}},
+	}
+	for _, tt := range tests {
+		c, err := NewQuotaCollector("test", opt, tt.include, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes := make(map[string]bool)
+		for name := range collect(t, c) {
+			for _, n := range []string{"/eos/user/", "/eos/project/atlas/", "/eos/scratch/"} {
+				if containsLabel(name, "quotanode", n) {
+					nodes[n] = true
+				}
+			}
+		}
+		if len(nodes) != len(tt.nodes) {
+			t.Errorf("include %q, exclude %q: exported %v, want %v", tt.include, tt.exclude, nodes, tt.nodes)
+		}
+		for _, n := range tt.nodes {
+			if !nodes[n] {
+				t.Errorf("include %q, exclude %q: %s not exported", tt.include, tt.exclude, n)
+			}
+		}
+	}
+
+	if _, err := NewQuotaCollector("test", opt, "(", ""); err == nil {
+		t.Error("NewQuotaCollector() accepted an invalid include expression")
+	}
+}
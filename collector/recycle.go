@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+type RecycleCollector struct {
+	opt *eosclient.Options
+
+	UsedBytes   *prometheus.Desc
+	MaxBytes    *prometheus.Desc
+	VolumeUsage *prometheus.Desc
+	InodeUsage  *prometheus.Desc
+	Lifetime    *prometheus.Desc
+	Ratio       *prometheus.Desc
+}
+
+// NewRecycleCollector creates an instance of the RecycleCollector
+func NewRecycleCollector(cluster string, opt *eosclient.Options) *RecycleCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &RecycleCollector{
+		opt: opt,
+
+		UsedBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_used_bytes"),
+			"Recycle bin bytes used",
+			nil,
+			labels,
+		),
+		MaxBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_max_bytes"),
+			"Recycle bin bytes allowed",
+			nil,
+			labels,
+		),
+		VolumeUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_volume_usage_percent"),
+			"Recycle bin percentage of the allowed bytes used",
+			nil,
+			labels,
+		),
+		InodeUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_inode_usage_percent"),
+			"Recycle bin percentage of the allowed inodes used",
+			nil,
+			labels,
+		),
+		Lifetime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_lifetime_seconds"),
+			"Recycle bin time the deleted files are kept",
+			nil,
+			labels,
+		),
+		Ratio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "recycle_keep_ratio"),
+			"Recycle bin usage ratio above which files are purged before their lifetime",
+			nil,
+			labels,
+		),
+	}
+}
+
+func (o *RecycleCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.UsedBytes,
+		o.MaxBytes,
+		o.VolumeUsage,
+		o.InodeUsage,
+		o.Lifetime,
+		o.Ratio,
+	}
+}
+
+func (o *RecycleCollector) collectRecycleDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	m, err := client.ListRecycle(ctx)
+	if err != nil {
+		return err
+	}
+
+	used, err := strconv.ParseFloat(m.UsedBytes, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.UsedBytes, prometheus.GaugeValue, used)
+	}
+
+	max, err := strconv.ParseFloat(m.MaxBytes, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.MaxBytes, prometheus.GaugeValue, max)
+	}
+
+	volume, err := strconv.ParseFloat(m.VolumeUsage, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.VolumeUsage, prometheus.GaugeValue, volume)
+	}
+
+	inode, err := strconv.ParseFloat(m.InodeUsage, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.InodeUsage, prometheus.GaugeValue, inode)
+	}
+
+	lifetime, err := strconv.ParseFloat(m.Lifetime, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.Lifetime, prometheus.GaugeValue, lifetime)
+	}
+
+	ratio, err := strconv.ParseFloat(m.Ratio, 64)
+	if err == nil {
+		ch <- prometheus.MustNewConstMetric(o.Ratio, prometheus.GaugeValue, ratio)
+	}
+
+	return nil
+
+} // collectRecycleDF()
+
+// Describe sends the descriptors of each RecycleCollector related metrics we have defined
+func (o *RecycleCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *RecycleCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectRecycleDF(ctx, ch)
+}
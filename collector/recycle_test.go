@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestRecycleCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"recycle -m": {Stdout: "recycle-bin=/eos/dev/proc/recycle/ usedbytes=100 maxbytes=1000 volumeusage=10.00% inodeusage=1.50% lifetime=604800 ratio=0.800000\n"},
+	})
+
+	got := collect(t, NewRecycleCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_recycle_used_bytes{cluster="test"}`:           100,
+		`eos_recycle_max_bytes{cluster="test"}`:            1000,
+		`eos_recycle_volume_usage_percent{cluster="test"}`: 10,
+		`eos_recycle_inode_usage_percent{cluster="test"}`:  1.5,
+		`eos_recycle_lifetime_seconds{cluster="test"}`:     604800,
+		`eos_recycle_keep_ratio{cluster="test"}`:           0.8,
+	})
+}
+
+func TestRecycleCollectorPartial(t *testing.T) {
+	// an older instance without a lifetime or ratio exports the rest of the values
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"recycle -m": {Stdout: "recycle-bin=/eos/dev/proc/recycle/ usedbytes=100 maxbytes=1000 volumeusage=10.00% inodeusage=1.50%\n"},
+	})
+
+	got := collect(t, NewRecycleCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_recycle_used_bytes{cluster="test"}`: 100,
+	})
+	checkAbsent(t, got, "eos_recycle_lifetime_seconds", "eos_recycle_keep_ratio")
+}
@@ -2,7 +2,6 @@ package collector
 
 import (
 	"context"
-	"log"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -10,300 +9,219 @@ import (
 )
 
 type SpaceCollector struct {
-	CfgGroupSize                        *prometheus.GaugeVec
-	CfgGroupMod                         *prometheus.GaugeVec
-	Nofs                                *prometheus.GaugeVec
-	AvgStatDiskLoad                     *prometheus.GaugeVec
-	SigStatDiskLoad                     *prometheus.GaugeVec
-	SumStatDiskReadratemb               *prometheus.GaugeVec
-	SumStatDiskWriteratemb              *prometheus.GaugeVec
-	SumStatNetEthratemib                *prometheus.GaugeVec
-	SumStatNetInratemib                 *prometheus.GaugeVec
-	SumStatNetOutratemib                *prometheus.GaugeVec
-	SumStatRopen                        *prometheus.GaugeVec
-	SumStatWopen                        *prometheus.GaugeVec
-	SumStatStatfsUsedbytes              *prometheus.GaugeVec
-	SumStatStatfsFreebytes              *prometheus.GaugeVec
-	SumStatStatfsCapacity               *prometheus.GaugeVec
-	SumStatUsedfiles                    *prometheus.GaugeVec
-	SumStatStatfsFfiles                 *prometheus.GaugeVec
-	SumStatStatfsFiles                  *prometheus.GaugeVec
-	SumStatStatfsCapacityConfigstatusRw *prometheus.GaugeVec
-	SumNofsConfigstatusRw               *prometheus.GaugeVec
-	CfgQuota                            *prometheus.GaugeVec
-	CfgNominalsize                      *prometheus.GaugeVec
-	CfgBalancer                         *prometheus.GaugeVec
-	CfgBalancerThreshold                *prometheus.GaugeVec
-	SumStatBalancerRunning              *prometheus.GaugeVec
-	SumStatDrainerRunning               *prometheus.GaugeVec
-	SumStatDiskIopsConfigstatusRw       *prometheus.GaugeVec
-	SumStatDiskBwConfigstatusRw         *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	CfgGroupSize                        *prometheus.Desc
+	CfgGroupMod                         *prometheus.Desc
+	Nofs                                *prometheus.Desc
+	AvgStatDiskLoad                     *prometheus.Desc
+	SigStatDiskLoad                     *prometheus.Desc
+	SumStatDiskReadratemb               *prometheus.Desc
+	SumStatDiskWriteratemb              *prometheus.Desc
+	SumStatNetEthratemib                *prometheus.Desc
+	SumStatNetInratemib                 *prometheus.Desc
+	SumStatNetOutratemib                *prometheus.Desc
+	SumStatRopen                        *prometheus.Desc
+	SumStatWopen                        *prometheus.Desc
+	SumStatStatfsUsedbytes              *prometheus.Desc
+	SumStatStatfsFreebytes              *prometheus.Desc
+	SumStatStatfsCapacity               *prometheus.Desc
+	SumStatUsedfiles                    *prometheus.Desc
+	SumStatStatfsFfiles                 *prometheus.Desc
+	SumStatStatfsFiles                  *prometheus.Desc
+	SumStatStatfsCapacityConfigstatusRw *prometheus.Desc
+	SumNofsConfigstatusRw               *prometheus.Desc
+	CfgQuota                            *prometheus.Desc
+	CfgNominalsize                      *prometheus.Desc
+	CfgBalancer                         *prometheus.Desc
+	CfgBalancerThreshold                *prometheus.Desc
+	SumStatBalancerRunning              *prometheus.Desc
+	SumStatDrainerRunning               *prometheus.Desc
+	SumStatDiskIopsConfigstatusRw       *prometheus.Desc
+	SumStatDiskBwConfigstatusRw         *prometheus.Desc
 }
 
 //NewSpaceCollector creates an cluster of the SpaceCollector
-func NewSpaceCollector(cluster string) *SpaceCollector {
+func NewSpaceCollector(cluster string, opt *eosclient.Options) *SpaceCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &SpaceCollector{
+		opt: opt,
 
-		CfgGroupSize: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_groupsize",
-				Help:        "Space Group Size",
-				ConstLabels: labels,
-			},
+		CfgGroupSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_groupsize"),
+			"Space Group Size",
 			[]string{"space"},
+			labels,
 		),
-		CfgGroupMod: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_groupmod",
-				Help:        "Space Group Mod",
-				ConstLabels: labels,
-			},
+		CfgGroupMod: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_groupmod"),
+			"Space Group Mod",
 			[]string{"space"},
+			labels,
 		),
-		Nofs: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_nofs",
-				Help:        "Space Number of filesystems",
-				ConstLabels: labels,
-			},
+		Nofs: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_nofs"),
+			"Space Number of filesystems",
 			[]string{"space"},
+			labels,
 		),
-		AvgStatDiskLoad: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_load_avg",
-				Help:        "Space Avg disk load",
-				ConstLabels: labels,
-			},
+		AvgStatDiskLoad: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_load_avg"),
+			"Space Avg disk load",
 			[]string{"space"},
+			labels,
 		),
-		SigStatDiskLoad: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_load_sig",
-				Help:        "Space Sig disk load",
-				ConstLabels: labels,
-			},
+		SigStatDiskLoad: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_load_sig"),
+			"Space Sig disk load",
 			[]string{"space"},
+			labels,
 		),
-		SumStatDiskReadratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_readratemb",
-				Help:        "Space Disk Read Rate in MB/s",
-				ConstLabels: labels,
-			},
+		SumStatDiskReadratemb: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_readratemb"),
+			"Space Disk Read Rate in MB/s",
 			[]string{"space"},
+			labels,
 		),
-		SumStatDiskWriteratemb: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_writeratemb",
-				Help:        "Space Sum Disk Write Rate in MB/s",
-				ConstLabels: labels,
-			},
+		SumStatDiskWriteratemb: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_writeratemb"),
+			"Space Sum Disk Write Rate in MB/s",
 			[]string{"space"},
+			labels,
 		),
-		SumStatNetEthratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_net_ethratemib",
-				Help:        "Space Net Eth Rate in MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetEthratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_net_ethratemib"),
+			"Space Net Eth Rate in MiB/s",
 			[]string{"space"},
+			labels,
 		),
-		SumStatNetInratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_net_inratemib",
-				Help:        "Space Net In Rate MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetInratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_net_inratemib"),
+			"Space Net In Rate MiB/s",
 			[]string{"space"},
+			labels,
 		),
-		SumStatNetOutratemib: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_net_outratemib",
-				Help:        "Space Net Out Rate MiB/s",
-				ConstLabels: labels,
-			},
+		SumStatNetOutratemib: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_net_outratemib"),
+			"Space Net Out Rate MiB/s",
 			[]string{"space"},
+			labels,
 		),
-		SumStatRopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_ropen",
-				Help:        "Space Open reads",
-				ConstLabels: labels,
-			},
+		SumStatRopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_ropen"),
+			"Space Open reads",
 			[]string{"space"},
+			labels,
 		),
-		SumStatWopen: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_disk_wopen",
-				Help:        "Space Open writes",
-				ConstLabels: labels,
-			},
+		SumStatWopen: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_disk_wopen"),
+			"Space Open writes",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsUsedbytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_usedbytes",
-				Help:        "Space StatFs Used Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatfsUsedbytes: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_usedbytes"),
+			"Space StatFs Used Bytes",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsFreebytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_freebytes",
-				Help:        "Space StatFs Free Bytes",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFreebytes: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_freebytes"),
+			"Space StatFs Free Bytes",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsCapacity: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_sizebytes",
-				Help:        "Space StatFs Size",
-				ConstLabels: labels,
-			},
+		SumStatStatfsCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_sizebytes"),
+			"Space StatFs Size",
 			[]string{"space"},
+			labels,
 		),
-		SumStatUsedfiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_usedfiles",
-				Help:        "Space Used Files",
-				ConstLabels: labels,
-			},
+		SumStatUsedfiles: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_usedfiles"),
+			"Space Used Files",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsFfiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_freefiles",
-				Help:        "Space Free Files",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFfiles: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_freefiles"),
+			"Space Free Files",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsFiles: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_files",
-				Help:        "Space Files",
-				ConstLabels: labels,
-			},
+		SumStatStatfsFiles: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_files"),
+			"Space Files",
 			[]string{"space"},
+			labels,
 		),
-		SumStatStatfsCapacityConfigstatusRw: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   "eos",
-				Name:        "space_statfs_sizebytes_configrw",
-				Help:        "Space StatFs Capacity ConfigStatus RW",
-				ConstLabels: labels,
-			},
+		SumStatStatfsCapacityConfigstatusRw: prometheus.NewDesc(
+			prometheus.BuildFQName("eos", "", "space_statfs_sizebytes_configrw"),
+			"Space StatFs Capacity ConfigStatus RW",
 			[]string{"space"},
+			labels,
 		),
-		SumNofsConfigstatusRw: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_nofs_configrw",
-				Help:        "Space Number of filesystems in FS with configstatus=rw",
-				ConstLabels: labels,
-			},
+		SumNofsConfigstatusRw: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_nofs_configrw"),
+			"Space Number of filesystems in FS with configstatus=rw",
 			[]string{"space"},
+			labels,
 		),
-		CfgQuota: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_quota",
-				Help:        "Space Quota Status: 0=off, 1=on",
-				ConstLabels: labels,
-			},
+		CfgQuota: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_quota"),
+			"Space Quota Status: 0=off, 1=on",
 			[]string{"space"},
+			labels,
 		),
-		CfgNominalsize: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_nominalsize",
-				Help:        "Space Nominal Size",
-				ConstLabels: labels,
-			},
+		CfgNominalsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_nominalsize"),
+			"Space Nominal Size",
 			[]string{"space"},
+			labels,
 		),
-		CfgBalancer: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_balancer_status",
-				Help:        "Space Group Balancer Status: 0=off, 1=on",
-				ConstLabels: labels,
-			},
+		CfgBalancer: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_balancer_status"),
+			"Space Group Balancer Status: 0=off, 1=on",
 			[]string{"space"},
+			labels,
 		),
-		CfgBalancerThreshold: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_cfg_balancer_threshold",
-				Help:        "Space Group Balancer Threshold",
-				ConstLabels: labels,
-			},
+		CfgBalancerThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_cfg_balancer_threshold"),
+			"Space Group Balancer Threshold",
 			[]string{"space"},
+			labels,
 		),
-		SumStatBalancerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_balancer_running",
-				Help:        "Space Stat Balancer Running",
-				ConstLabels: labels,
-			},
+		SumStatBalancerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_balancer_running"),
+			"Space Stat Balancer Running",
 			[]string{"space"},
+			labels,
 		),
-		SumStatDrainerRunning: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_drainer_running",
-				Help:        "Space Stat Drainer Running",
-				ConstLabels: labels,
-			},
+		SumStatDrainerRunning: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_drainer_running"),
+			"Space Stat Drainer Running",
 			[]string{"space"},
+			labels,
 		),
-		SumStatDiskIopsConfigstatusRw: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_disk_iops_configrw",
-				Help:        "Space Stat Disk IOPS configstatus=rw",
-				ConstLabels: labels,
-			},
+		SumStatDiskIopsConfigstatusRw: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_disk_iops_configrw"),
+			"Space Stat Disk IOPS configstatus=rw",
 			[]string{"space"},
+			labels,
 		),
-		SumStatDiskBwConfigstatusRw: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "space_disk_bw_configrw",
-				Help:        "Space Stat Disk Bandwidth configstatus=rw",
-				ConstLabels: labels,
-			},
+		SumStatDiskBwConfigstatusRw: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "space_disk_bw_configrw"),
+			"Space Stat Disk Bandwidth configstatus=rw",
 			[]string{"space"},
+			labels,
 		),
 	}
 }
 
-func (o *SpaceCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *SpaceCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		o.CfgGroupSize,
 		o.CfgGroupMod,
 		o.Nofs,
@@ -335,125 +253,122 @@ func (o *SpaceCollector) collectorList() []prometheus.Collector {
 	}
 }
 
-func (o *SpaceCollector) collectSpaceDF() error {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
+func (o *SpaceCollector) collectSpaceDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	mds, err := client.ListSpace(context.Background(), "root")
+	mds, err := client.ListSpace(ctx)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	for _, m := range mds {
 
 		nofs, err := strconv.ParseFloat(m.Nofs, 64)
 		if err == nil {
-			o.Nofs.WithLabelValues(m.Name).Set(nofs)
+			ch <- prometheus.MustNewConstMetric(o.Nofs, prometheus.GaugeValue, nofs, m.Name)
 		}
 
 		avgdl, err := strconv.ParseFloat(m.AvgStatDiskLoad, 64)
 		if err == nil {
-			o.AvgStatDiskLoad.WithLabelValues(m.Name).Set(avgdl)
+			ch <- prometheus.MustNewConstMetric(o.AvgStatDiskLoad, prometheus.GaugeValue, avgdl, m.Name)
 		}
 
 		sigdl, err := strconv.ParseFloat(m.SigStatDiskLoad, 64)
 		if err == nil {
-			o.SigStatDiskLoad.WithLabelValues(m.Name).Set(sigdl)
+			ch <- prometheus.MustNewConstMetric(o.SigStatDiskLoad, prometheus.GaugeValue, sigdl, m.Name)
 		}
 
 		sumdiskr, err := strconv.ParseFloat(m.SumStatDiskReadratemb, 64)
 		if err == nil {
-			o.SumStatDiskReadratemb.WithLabelValues(m.Name).Set(sumdiskr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskReadratemb, prometheus.GaugeValue, sumdiskr, m.Name)
 		}
 
 		sumdiskw, err := strconv.ParseFloat(m.SumStatDiskWriteratemb, 64)
 		if err == nil {
-			o.SumStatDiskWriteratemb.WithLabelValues(m.Name).Set(sumdiskw)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskWriteratemb, prometheus.GaugeValue, sumdiskw, m.Name)
 		}
 
 		sumethrate, err := strconv.ParseFloat(m.SumStatNetEthratemib, 64)
 		if err == nil {
-			o.SumStatNetEthratemib.WithLabelValues(m.Name).Set(sumethrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetEthratemib, prometheus.GaugeValue, sumethrate, m.Name)
 		}
 
 		suminrate, err := strconv.ParseFloat(m.SumStatNetInratemib, 64)
 		if err == nil {
-			o.SumStatNetInratemib.WithLabelValues(m.Name).Set(suminrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetInratemib, prometheus.GaugeValue, suminrate, m.Name)
 		}
 
 		sumoutrate, err := strconv.ParseFloat(m.SumStatNetOutratemib, 64)
 		if err == nil {
-			o.SumStatNetOutratemib.WithLabelValues(m.Name).Set(sumoutrate)
+			ch <- prometheus.MustNewConstMetric(o.SumStatNetOutratemib, prometheus.GaugeValue, sumoutrate, m.Name)
 		}
 
 		ropen, err := strconv.ParseFloat(m.SumStatRopen, 64)
 		if err == nil {
-			o.SumStatRopen.WithLabelValues(m.Name).Set(ropen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatRopen, prometheus.GaugeValue, ropen, m.Name)
 		}
 
 		wopen, err := strconv.ParseFloat(m.SumStatWopen, 64)
 		if err == nil {
-			o.SumStatWopen.WithLabelValues(m.Name).Set(wopen)
+			ch <- prometheus.MustNewConstMetric(o.SumStatWopen, prometheus.GaugeValue, wopen, m.Name)
 		}
 
 		usedb, err := strconv.ParseFloat(m.SumStatStatfsUsedbytes, 64)
 		if err == nil {
-			o.SumStatStatfsUsedbytes.WithLabelValues(m.Name).Set(usedb)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsUsedbytes, prometheus.GaugeValue, usedb, m.Name)
 		}
 
 		fbytes, err := strconv.ParseFloat(m.SumStatStatfsFreebytes, 64)
 		if err == nil {
-			o.SumStatStatfsFreebytes.WithLabelValues(m.Name).Set(fbytes)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFreebytes, prometheus.GaugeValue, fbytes, m.Name)
 		}
 
 		fscap, err := strconv.ParseFloat(m.SumStatStatfsCapacity, 64)
 		if err == nil {
-			o.SumStatStatfsCapacity.WithLabelValues(m.Name).Set(fscap)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsCapacity, prometheus.GaugeValue, fscap, m.Name)
 		}
 
 		ufiles, err := strconv.ParseFloat(m.SumStatUsedfiles, 64)
 		if err == nil {
-			o.SumStatUsedfiles.WithLabelValues(m.Name).Set(ufiles)
+			ch <- prometheus.MustNewConstMetric(o.SumStatUsedfiles, prometheus.GaugeValue, ufiles, m.Name)
 		}
 
 		files, err := strconv.ParseFloat(m.SumStatStatfsFiles, 64)
 		if err == nil {
-			o.SumStatStatfsFiles.WithLabelValues(m.Name).Set(files)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsFiles, prometheus.GaugeValue, files, m.Name)
 		}
 
 		caprw, err := strconv.ParseFloat(m.SumStatStatfsCapacityConfigstatusRw, 64)
 		if err == nil {
-			o.SumStatStatfsCapacityConfigstatusRw.WithLabelValues(m.Name).Set(caprw)
+			ch <- prometheus.MustNewConstMetric(o.SumStatStatfsCapacityConfigstatusRw, prometheus.GaugeValue, caprw, m.Name)
 		}
 
 		nofsrw, err := strconv.ParseFloat(m.SumNofsConfigstatusRw, 64)
 		if err == nil {
-			o.SumNofsConfigstatusRw.WithLabelValues(m.Name).Set(nofsrw)
+			ch <- prometheus.MustNewConstMetric(o.SumNofsConfigstatusRw, prometheus.GaugeValue, nofsrw, m.Name)
 		}
 
 		balr, err := strconv.ParseFloat(m.SumStatBalancerRunning, 64)
 		if err == nil {
-			o.SumStatBalancerRunning.WithLabelValues(m.Name).Set(balr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatBalancerRunning, prometheus.GaugeValue, balr, m.Name)
 		}
 
 		drainr, err := strconv.ParseFloat(m.SumStatDrainerRunning, 64)
 		if err == nil {
-			o.SumStatDrainerRunning.WithLabelValues(m.Name).Set(drainr)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDrainerRunning, prometheus.GaugeValue, drainr, m.Name)
 		}
 
 		iopsrw, err := strconv.ParseFloat(m.SumStatDiskIopsConfigstatusRw, 64)
 		if err == nil {
-			o.SumStatDiskIopsConfigstatusRw.WithLabelValues(m.Name).Set(iopsrw)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskIopsConfigstatusRw, prometheus.GaugeValue, iopsrw, m.Name)
 		}
 
 		bwrw, err := strconv.ParseFloat(m.SumStatDiskBwConfigstatusRw, 64)
 		if err == nil {
-			o.SumStatDiskBwConfigstatusRw.WithLabelValues(m.Name).Set(bwrw)
+			ch <- prometheus.MustNewConstMetric(o.SumStatDiskBwConfigstatusRw, prometheus.GaugeValue, bwrw, m.Name)
 		}
 
 		// Balancer Status
@@ -466,21 +381,21 @@ func (o *SpaceCollector) collectSpaceDF() error {
 			balancer_status = 0
 		}
 
-		o.CfgBalancer.WithLabelValues(m.Name).Set(float64(balancer_status))
+		ch <- prometheus.MustNewConstMetric(o.CfgBalancer, prometheus.GaugeValue, float64(balancer_status), m.Name)
 
 		balt, err := strconv.ParseFloat(m.CfgBalancerThreshold, 64)
 		if err == nil {
-			o.CfgBalancerThreshold.WithLabelValues(m.Name).Set(balt)
+			ch <- prometheus.MustNewConstMetric(o.CfgBalancerThreshold, prometheus.GaugeValue, balt, m.Name)
 		}
 
 		gsize, err := strconv.ParseFloat(m.CfgGroupSize, 64)
 		if err == nil {
-			o.CfgGroupSize.WithLabelValues(m.Name).Set(gsize)
+			ch <- prometheus.MustNewConstMetric(o.CfgGroupSize, prometheus.GaugeValue, gsize, m.Name)
 		}
 
 		gmod, err := strconv.ParseFloat(m.CfgGroupMod, 64)
 		if err == nil {
-			o.CfgGroupMod.WithLabelValues(m.Name).Set(gmod)
+			ch <- prometheus.MustNewConstMetric(o.CfgGroupMod, prometheus.GaugeValue, gmod, m.Name)
 		}
 
 		// Quota Status
@@ -493,11 +408,11 @@ func (o *SpaceCollector) collectSpaceDF() error {
 			quota_status = 0
 		}
 
-		o.CfgQuota.WithLabelValues(m.Name).Set(float64(quota_status))
+		ch <- prometheus.MustNewConstMetric(o.CfgQuota, prometheus.GaugeValue, float64(quota_status), m.Name)
 
 		nomsize, err := strconv.ParseFloat(m.CfgNominalsize, 64)
 		if err == nil {
-			o.CfgNominalsize.WithLabelValues(m.Name).Set(nomsize)
+			ch <- prometheus.MustNewConstMetric(o.CfgNominalsize, prometheus.GaugeValue, nomsize, m.Name)
 		}
 
 	}
@@ -508,19 +423,12 @@ func (o *SpaceCollector) collectSpaceDF() error {
 
 // Describe sends the descriptors of each SpaceCollector related metrics we have defined
 func (o *SpaceCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *SpaceCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectSpaceDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *SpaceCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectSpaceDF(ctx, ch)
 }
@@ -0,0 +1,32 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestSpaceCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"space ls -m": {Stdout: "type=spaceview name=default cfg.groupsize=24 cfg.groupmod=24 nofs=3 avg.stat.disk.load=0.1 sum.stat.disk.readratemb=10 sum.stat.statfs.usedbytes=55 sum.stat.statfs.capacity=165 sum.stat.statfs.freebytes=110 cfg.quota=on cfg.balancer=on cfg.balancer.threshold=20 sum.stat.statfs.capacity?configstatus@rw=110 sum.<n>?configstatus@rw=2\n" +
+			"type=spaceview name=spare nofs=0 cfg.quota=off\n"},
+	})
+
+	got := collect(t, NewSpaceCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_space_nofs{cluster="test",space="default"}`:                      3,
+		`eos_space_nofs{cluster="test",space="spare"}`:                        0,
+		`eos_space_nofs_configrw{cluster="test",space="default"}`:             2,
+		`eos_space_cfg_groupsize{cluster="test",space="default"}`:             24,
+		`eos_space_disk_load_avg{cluster="test",space="default"}`:             0.1,
+		`eos_space_statfs_usedbytes{cluster="test",space="default"}`:          55,
+		`eos_space_statfs_sizebytes{cluster="test",space="default"}`:          165,
+		`eos_space_statfs_sizebytes_configrw{cluster="test",space="default"}`: 110,
+		`eos_space_cfg_quota{cluster="test",space="default"}`:                 1,
+		`eos_space_cfg_quota{cluster="test",space="spare"}`:                   0,
+		`eos_space_cfg_balancer_status{cluster="test",space="default"}`:       1,
+		`eos_space_cfg_balancer_threshold{cluster="test",space="default"}`:    20,
+	})
+	// values missing from the listing are not exported
+	checkAbsent(t, got, `eos_space_cfg_groupsize{cluster="test",space="spare"}`, `eos_space_statfs_usedbytes{cluster="test",space="spare"}`)
+}
@@ -2,7 +2,6 @@ package collector
 
 import (
 	"context"
-	"log"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,86 +13,71 @@ import (
 )
 
 type VSCollector struct {
-	EOSmgm    *prometheus.GaugeVec
-	Hostport  *prometheus.GaugeVec
-	Geotag    *prometheus.GaugeVec
-	Vsize     *prometheus.GaugeVec
-	Rss       *prometheus.GaugeVec
-	Threads   *prometheus.GaugeVec
-	Versions  *prometheus.GaugeVec
-	EOSfst    *prometheus.GaugeVec
-	Xrootdfst *prometheus.GaugeVec
-	KernelV   *prometheus.GaugeVec
-	Start     *prometheus.GaugeVec
-	Uptime    *prometheus.GaugeVec
+	opt *eosclient.Options
+
+	EOSmgm    *prometheus.Desc
+	Hostport  *prometheus.Desc
+	Geotag    *prometheus.Desc
+	Vsize     *prometheus.Desc
+	Rss       *prometheus.Desc
+	Threads   *prometheus.Desc
+	Versions  *prometheus.Desc
+	EOSfst    *prometheus.Desc
+	Xrootdfst *prometheus.Desc
+	KernelV   *prometheus.Desc
+	Start     *prometheus.Desc
+	Uptime    *prometheus.Desc
 }
 
 //NewFSCollector creates an cluster of the FSCollector and instantiates
 // the individual metrics that show information about the FS.
-func NewVSCollector(cluster string) *VSCollector {
+func NewVSCollector(cluster string, opt *eosclient.Options) *VSCollector {
 	labels := make(prometheus.Labels)
 	labels["cluster"] = cluster
 	namespace := "eos"
 	return &VSCollector{
-		Vsize: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_vsize_bytes",
-				Help:        "Vsize: ",
-				ConstLabels: labels,
-			},
+		opt: opt,
+		Vsize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_vsize_bytes"),
+			"Vsize: ",
 			[]string{"mgm_version", "node", "geotag", "eos_v_fst", "xrd_v_fst", "kernel_v"},
+			labels,
 		),
-		Rss: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_rss_bytes",
-				Help:        "Rss: ",
-				ConstLabels: labels,
-			},
+		Rss: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_rss_bytes"),
+			"Rss: ",
 			[]string{"mgm_version", "node", "geotag", "eos_v_fst", "xrd_v_fst", "kernel_v"},
+			labels,
 		),
-		Threads: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_threads_total",
-				Help:        "Threads: ",
-				ConstLabels: labels,
-			},
+		Threads: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_threads_total"),
+			"Threads: ",
 			[]string{"mgm_version", "node", "geotag", "eos_v_fst", "xrd_v_fst", "kernel_v"},
+			labels,
 		),
-		Versions: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_total",
-				Help:        "Verions: Amount of daemons attached to a node",
-				ConstLabels: labels,
-			},
+		Versions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_total"),
+			"Verions: Amount of daemons attached to a node",
 			[]string{"mgm_version", "node", "port", "geotag", "eos_v_fst", "xrd_v_fst", "kernel_v"},
+			labels,
 		),
-		Uptime: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_uptime_seconds",
-				Help:        "Uptime: Amount of seconds the FST has been up",
-				ConstLabels: labels,
-			},
+		Uptime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_uptime_seconds"),
+			"Uptime: Amount of seconds the FST has been up",
 			[]string{"node"},
+			labels,
 		),
-		Start: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "versions_start_seconds",
-				Help:        "Start: Time when EOS was started.",
-				ConstLabels: labels,
-			},
+		Start: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "versions_start_seconds"),
+			"Start: Time when EOS was started.",
 			[]string{"mgm_version", "node", "geotag", "eos_v_fst", "xrd_v_fst", "kernel_v"},
+			labels,
 		),
 	}
 }
 
-func (o *VSCollector) collectorList() []prometheus.Collector {
-	return []prometheus.Collector{
+func (o *VSCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
 		//	o.EOSmgm,
 		//	o.Hostport,
 		//	o.Geotag,
@@ -109,34 +93,38 @@ func (o *VSCollector) collectorList() []prometheus.Collector {
 	}
 }
 
-func (o *VSCollector) collectVSDF() error {
-	ins := getEOSInstance()
-	url := "root://" + ins + ".cern.ch"
-	opt := &eosclient.Options{URL: url}
-	client, err := eosclient.New(opt)
+func (o *VSCollector) collectVSDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	mds, err := client.ListVS(context.Background())
+	mds, err := client.ListVS(ctx)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
+	// Several FSTs can run on the same host, report its uptime only once.
+	seen := make(map[string]struct{})
 	for _, m := range mds {
 
 		// Versions
 
 		versions, err := strconv.ParseFloat("1", 64)
 		if err == nil {
-			o.Versions.WithLabelValues(m.EOSmgm, m.Hostname, m.Port, m.Geotag, m.EOSfst, m.Xrootdfst, m.KernelV).Set(versions)
+			ch <- prometheus.MustNewConstMetric(o.Versions, prometheus.GaugeValue, versions, m.EOSmgm, m.Hostname, m.Port, m.Geotag, m.EOSfst, m.Xrootdfst, m.KernelV)
 		}
 
 		// Uptime
 
+		if _, ok := seen[m.Hostname]; ok {
+			continue
+		}
+		seen[m.Hostname] = struct{}{}
+
 		uptime, err := strconv.ParseFloat(m.Uptime, 64)
 		if err == nil {
-			o.Uptime.WithLabelValues(m.Hostname).Set(uptime * 3600 * 24)
+			ch <- prometheus.MustNewConstMetric(o.Uptime, prometheus.GaugeValue, uptime*3600*24, m.Hostname)
 		}
 	}
 
@@ -146,20 +134,13 @@ func (o *VSCollector) collectVSDF() error {
 
 // Describe sends the descriptors of each VSCollector related metrics we have defined
 func (o *VSCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, metric := range o.collectorList() {
-		metric.Describe(ch)
+	for _, desc := range o.descList() {
+		ch <- desc
 	}
 	//ch <- o.ScrubbingStateDesc
 }
 
-// Collect sends all the collected metrics to the provided prometheus channel.
-func (o *VSCollector) Collect(ch chan<- prometheus.Metric) {
-
-	if err := o.collectVSDF(); err != nil {
-		log.Println("failed collecting space metrics:", err)
-	}
-
-	for _, metric := range o.collectorList() {
-		metric.Collect(ch)
-	}
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *VSCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectVSDF(ctx, ch)
 }
@@ -0,0 +1,20 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestVSCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"version":        {Stdout: "EOS_INSTANCE=eostest\nEOS_SERVER_VERSION=5.1.0 EOS_SERVER_RELEASE=1\n"},
+		"--json node ls": {Stdout: `{"errormsg":"","result":[{"hostport":"fst1.example.org:1095","cfg":{"stat":{"geotag":"site::rack1","sys":{"eos":{"start":"Mon","version":"5.1.1"},"kernel":"5.14","rss":1,"sockets":2,"threads":3,"uptime":"10:00%20up%2012%20days,","vsize":4,"xrootd":{"version":"5.5"}}}}}]}`},
+	})
+
+	got := collect(t, NewVSCollector("test", opt))
+	checkSeries(t, got, map[string]float64{
+		`eos_versions_total{cluster="test",eos_v_fst="5.1.1",geotag="site::rack1",kernel_v="5.14",mgm_version="5.1.0",node="fst1.example.org",port="1095",xrd_v_fst="5.5"}`: 1,
+		`eos_versions_uptime_seconds{cluster="test",node="fst1.example.org"}`:                                                                                               12 * 24 * 3600,
+	})
+}
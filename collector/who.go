@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+type WhoCollector struct {
+	opt *eosclient.Options
+
+	// topUsers is the number of users with the most sessions exported, none when 0.
+	topUsers int
+
+	Sessions     *prometheus.Desc
+	UserSessions *prometheus.Desc
+}
+
+// NewWhoCollector creates an instance of the WhoCollector.
+// The sessions of the topUsers users with the most of them are exported too.
+func NewWhoCollector(cluster string, opt *eosclient.Options, topUsers int) *WhoCollector {
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &WhoCollector{
+		opt:      opt,
+		topUsers: topUsers,
+
+		Sessions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "who_sessions"),
+			"Who number of client sessions, by protocol and authentication method",
+			[]string{"protocol", "auth"},
+			labels,
+		),
+		UserSessions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "who_user_sessions"),
+			"Who number of client sessions of the users with the most sessions",
+			[]string{"uid"},
+			labels,
+		),
+	}
+}
+
+func (o *WhoCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Sessions,
+		o.UserSessions,
+	}
+}
+
+// whoProtocol returns the protocol of a session. When eos does not tell it,
+// it is guessed from the authentication method and the application of the client.
+func whoProtocol(m *eosclient.WhoInfo) string {
+	if m.Protocol != "" {
+		return m.Protocol
+	}
+	app := strings.ToLower(m.App)
+	switch {
+	case strings.HasPrefix(app, "fuse") || strings.HasPrefix(app, "eosxd"):
+		return "fuse"
+	case strings.HasPrefix(app, "grpc") || m.Auth == "grpc":
+		return "grpc"
+	case strings.HasPrefix(app, "http") || m.Auth == "https" || m.Auth == "http":
+		return "http"
+	default:
+		return "xroot"
+	}
+}
+
+func (o *WhoCollector) collectWhoDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		return err
+	}
+
+	mds, err := client.ListWho(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessions := make(map[[2]string]float64)
+	users := make(map[string]float64)
+	for _, m := range mds {
+		sessions[[2]string{whoProtocol(m), m.Auth}]++
+		users[m.Uid]++
+	}
+
+	for k, count := range sessions {
+		ch <- prometheus.MustNewConstMetric(o.Sessions, prometheus.GaugeValue, count, k[0], k[1])
+	}
+
+	if o.topUsers > 0 {
+		uids := make([]string, 0, len(users))
+		for uid := range users {
+			uids = append(uids, uid)
+		}
+		sort.Slice(uids, func(i, j int) bool {
+			if users[uids[i]] != users[uids[j]] {
+				return users[uids[i]] > users[uids[j]]
+			}
+			return uids[i] < uids[j]
+		})
+		if len(uids) > o.topUsers {
+			uids = uids[:o.topUsers]
+		}
+		for _, uid := range uids {
+			ch <- prometheus.MustNewConstMetric(o.UserSessions, prometheus.GaugeValue, users[uid], uid)
+		}
+	}
+
+	return nil
+
+} // collectWhoDF()
+
+// Describe sends the descriptors of each WhoCollector related metrics we have defined
+func (o *WhoCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *WhoCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectWhoDF(ctx, ch)
+}
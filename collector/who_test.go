@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"testing"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+func TestWhoProtocol(t *testing.T) {
+	tests := []struct {
+		info eosclient.WhoInfo
+		want string
+	}{
+		{eosclient.WhoInfo{Auth: "krb5", Protocol: "https"}, "https"},
+		{eosclient.WhoInfo{Auth: "krb5"}, "xroot"},
+		{eosclient.WhoInfo{Auth: "unix", App: "fuse::lxplus"}, "fuse"},
+		{eosclient.WhoInfo{Auth: "sss", App: "eosxd"}, "fuse"},
+		{eosclient.WhoInfo{Auth: "grpc"}, "grpc"},
+		{eosclient.WhoInfo{Auth: "https"}, "http"},
+		{eosclient.WhoInfo{Auth: "unix", App: "HTTP"}, "http"},
+	}
+	for _, tt := range tests {
+		if got := whoProtocol(&tt.info); got != tt.want {
+			t.Errorf("whoProtocol(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestWhoCollector(t *testing.T) {
+	opt := fakeOptions(map[string]eosclient.FakeResponse{
+		"who -a -m": {Stdout: "auth=krb5 nsessions=2\n" +
+			"client=alice.1:2@lxplus1 uid=alice auth=krb5 idle=3 gateway=\"\" app=\"\"\n" +
+			"client=alice.1:3@lxplus1 uid=alice auth=unix idle=3 gateway=\"\" app=\"fuse\"\n" +
+			"client=bob.1:2@lxplus2 uid=bob auth=https idle=3 gateway=\"gw\" app=\"http\"\n" +
+			"client=carl.1:2@lxplus2 uid=carl auth=krb5 idle=3 gateway=\"\" app=\"\"\n"},
+	})
+
+	tests := []struct {
+		topUsers int
+		want     map[string]float64
+		absent   []string
+	}{
+		{0, map[string]float64{
+			`eos_who_sessions{auth="krb5",cluster="test",protocol="xroot"}`: 2,
+			`eos_who_sessions{auth="unix",cluster="test",protocol="fuse"}`:  1,
+			`eos_who_sessions{auth="https",cluster="test",protocol="http"}`: 1,
+		}, []string{"eos_who_user_sessions"}},
+		// bob and carl tie, the first uid by name is kept
+		{2, map[string]float64{
+			`eos_who_user_sessions{cluster="test",uid="alice"}`: 2,
+			`eos_who_user_sessions{cluster="test",uid="bob"}`:   1,
+		}, []string{`eos_who_user_sessions{cluster="test",uid="carl"}`}},
+	}
+	for _, tt := range tests {
+		got := collect(t, NewWhoCollector("test", opt, tt.topUsers))
+		checkSeries(t, got, tt.want)
+		checkAbsent(t, got, tt.absent...)
+	}
+}
@@ -0,0 +1,239 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// xrdcopyProbeSize is the size of the file written by the probe.
+const xrdcopyProbeSize = 4096
+
+type XrdcopyCollector struct {
+	opt *eosclient.Options
+
+	// path is the EOS directory where the probe files are written, one per space.
+	path   string
+	spaces []string
+
+	// interval is the time between two probes, each of them has to finish within it.
+	interval time.Duration
+
+	// metrics holds the results of the last probe, nil until the first one finishes.
+	mu      sync.Mutex
+	metrics []prometheus.Metric
+	err     error
+
+	Success   *prometheus.Desc
+	Duration  *prometheus.Desc
+	Timestamp *prometheus.Desc
+}
+
+// NewXrdcopyCollector creates an instance of the XrdcopyCollector.
+// Once started, it writes a file in dir on each of the spaces, reads it back, checks its checksum, stats and deletes it,
+// every interval.
+func NewXrdcopyCollector(cluster string, opt *eosclient.Options, dir string, spaces []string, interval time.Duration) (*XrdcopyCollector, error) {
+	if dir == "" {
+		return nil, errors.New("the xrdcopy collector requires the EOS directory where to write")
+	}
+	if interval <= 0 {
+		return nil, errors.New("the xrdcopy collector requires a positive interval")
+	}
+	if len(spaces) == 0 {
+		spaces = []string{"default"}
+	}
+
+	labels := make(prometheus.Labels)
+	labels["cluster"] = cluster
+	namespace := "eos"
+	return &XrdcopyCollector{
+		opt:      opt,
+		path:     dir,
+		spaces:   spaces,
+		interval: interval,
+
+		Success: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "xrdcopy_probe_success"),
+			"Xrdcopy probe outcome: 1 if the file was written, read back with the right checksum, stat'ed and deleted",
+			[]string{"space"},
+			labels,
+		),
+		Duration: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "xrdcopy_probe_duration_seconds"),
+			"Xrdcopy probe duration of each phase: write, stat, read, delete",
+			[]string{"space", "phase"},
+			labels,
+		),
+		Timestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "xrdcopy_probe_timestamp_seconds"),
+			"Xrdcopy probe time of the last run",
+			[]string{"space"},
+			labels,
+		),
+	}, nil
+}
+
+func (o *XrdcopyCollector) descList() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		o.Success,
+		o.Duration,
+		o.Timestamp,
+	}
+}
+
+// probe runs the phases on one space and returns the resulting metrics.
+// A phase is only run when the previous ones succeeded, except the delete which follows any successful write.
+// The delete is not bound to ctx, so the file is not left behind when the probe runs out of time.
+func (o *XrdcopyCollector) probe(ctx context.Context, client *eosclient.Client, space string) []prometheus.Metric {
+	var metrics []prometheus.Metric
+	start := time.Now()
+	phase := func(name string, f func() error) bool {
+		begin := time.Now()
+		err := f()
+		metrics = append(metrics, prometheus.MustNewConstMetric(o.Duration, prometheus.GaugeValue, time.Since(begin).Seconds(), space, name))
+		if err != nil {
+			log.Errorf("xrdcopy probe of space %s failed to %s: %s", space, name, err)
+			return false
+		}
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	file := path.Join(o.path, fmt.Sprintf("eos_exporter.%s.%s", hostname, space))
+	data := make([]byte, xrdcopyProbeSize)
+	rand.Read(data)
+	checksum := adler32.Checksum(data)
+
+	success := false
+	local, err := ioutil.TempFile(o.opt.CacheDirectory, "eos_exporter-")
+	if err == nil {
+		_, err = local.Write(data)
+		local.Close()
+		defer os.Remove(local.Name())
+	}
+	if err != nil {
+		log.Errorf("xrdcopy probe of space %s failed to create the local file: %s", space, err)
+	} else if phase("write", func() error { return client.Upload(ctx, local.Name(), file, space) }) {
+		success = phase("stat", func() error {
+			info, err := client.FileInfo(ctx, file)
+			if err != nil {
+				return err
+			}
+			if info.Size != strconv.Itoa(len(data)) {
+				return fmt.Errorf("size %s instead of %d", info.Size, len(data))
+			}
+			if info.XSType == "adler" {
+				xs, err := strconv.ParseUint(info.XS, 16, 32)
+				if err != nil || uint32(xs) != checksum {
+					return fmt.Errorf("checksum %s instead of %08x", info.XS, checksum)
+				}
+			}
+			return nil
+		}) && phase("read", func() error {
+			downloaded, err := client.Download(ctx, file)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(downloaded)
+			read, err := ioutil.ReadFile(downloaded)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(read, data) {
+				return fmt.Errorf("checksum %08x instead of %08x", adler32.Checksum(read), checksum)
+			}
+			return nil
+		})
+		success = phase("delete", func() error { return client.Remove(context.Background(), file) }) && success
+	}
+
+	value := 0.0
+	if success {
+		value = 1
+	}
+	return append(metrics,
+		prometheus.MustNewConstMetric(o.Success, prometheus.GaugeValue, value, space),
+		prometheus.MustNewConstMetric(o.Timestamp, prometheus.GaugeValue, float64(start.Unix()), space),
+	)
+}
+
+// run probes all the spaces, within the interval.
+func (o *XrdcopyCollector) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, o.interval)
+	defer cancel()
+
+	client, err := newEOSClient(o.opt)
+	if err != nil {
+		log.Errorf("xrdcopy probe failed: %s", err)
+		o.mu.Lock()
+		o.metrics, o.err = nil, err
+		o.mu.Unlock()
+		return
+	}
+
+	var metrics []prometheus.Metric
+	for _, space := range o.spaces {
+		metrics = append(metrics, o.probe(ctx, client, space)...)
+	}
+
+	o.mu.Lock()
+	o.metrics, o.err = metrics, nil
+	o.mu.Unlock()
+}
+
+// Start probes the spaces in the background every interval, until ctx is done.
+func (o *XrdcopyCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+		for {
+			o.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (o *XrdcopyCollector) collectXrdcopyDF(ctx context.Context, ch chan<- prometheus.Metric) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.err != nil {
+		return o.err
+	}
+
+	for _, m := range o.metrics {
+		ch <- m
+	}
+
+	return nil
+
+} // collectXrdcopyDF()
+
+// Describe sends the descriptors of each XrdcopyCollector related metrics we have defined
+func (o *XrdcopyCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range o.descList() {
+		ch <- desc
+	}
+}
+
+// Update sends all the collected metrics to the provided prometheus channel.
+func (o *XrdcopyCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return o.collectXrdcopyDF(ctx, ch)
+}
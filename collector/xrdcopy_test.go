@@ -0,0 +1,199 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"hash/adler32"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// storeRunner keeps the files copied with xrdcopy in memory and answers the file info and rm of them,
+// the other commands are served by the FakeRunner.
+type storeRunner struct {
+	*eosclient.FakeRunner
+	url string
+
+	// uploaded is called after each upload
+	uploaded func()
+
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newStoreRunner(url string, responses map[string]eosclient.FakeResponse) *storeRunner {
+	return &storeRunner{
+		FakeRunner: eosclient.NewFakeRunner(responses),
+		url:        url,
+		files:      make(map[string][]byte),
+	}
+}
+
+// path returns the EOS path of an xrootd URL, or "" for a local file.
+func (s *storeRunner) path(name string) string {
+	if !strings.HasPrefix(name, s.url+"/") {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(name, s.url+"/"), "?", 2)[0]
+}
+
+func (s *storeRunner) Run(ctx context.Context, env []string, name string, args ...string) (string, string, int, error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", -1, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case name == "xrdcopy" && len(args) == 4:
+		src, dst := args[2], args[3]
+		if p := s.path(dst); p != "" {
+			data, err := ioutil.ReadFile(src)
+			if err != nil {
+				return "", err.Error(), 1, err
+			}
+			s.files[p] = data
+			if s.uploaded != nil {
+				s.uploaded()
+			}
+			return "", "", 0, nil
+		}
+		data, ok := s.files[s.path(src)]
+		if !ok {
+			return "", "no such file", 1, nil
+		}
+		if err := ioutil.WriteFile(dst, data, 0600); err != nil {
+			return "", err.Error(), 1, err
+		}
+		return "", "", 0, nil
+	case len(args) == 7 && args[3] == "file" && args[4] == "info":
+		data, ok := s.files[args[5]]
+		if !ok {
+			return "", "no such file", 2, nil
+		}
+		return fmt.Sprintf("keylength.file=%d file=%s size=%d xstype=adler xs=%08x\n", len(args[5]), args[5], len(data), adler32.Checksum(data)), "", 0, nil
+	case len(args) == 5 && args[3] == "rm":
+		delete(s.files, args[4])
+		return "", "", 0, nil
+	}
+	return s.FakeRunner.Run(ctx, env, name, args...)
+}
+
+// xrdcopyOptions returns the client options of a probe using runner.
+func xrdcopyOptions(t *testing.T, runner eosclient.Runner) *eosclient.Options {
+	opt := fakeOptions(nil)
+	opt.XrdcopyBinary = "xrdcopy"
+	opt.CacheDirectory = t.TempDir()
+	opt.Runner = runner
+	return opt
+}
+
+// probeSeries runs a probe and returns the series the collector sends afterwards.
+func probeSeries(t *testing.T, ctx context.Context, opt *eosclient.Options, spaces ...string) map[string]float64 {
+	t.Helper()
+	c, err := NewXrdcopyCollector("test", opt, "/eos/test/probe", spaces, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.run(ctx)
+	return collect(t, c)
+}
+
+func TestNewXrdcopyCollector(t *testing.T) {
+	opt := fakeOptions(nil)
+	if _, err := NewXrdcopyCollector("test", opt, "", nil, time.Minute); err == nil {
+		t.Error("NewXrdcopyCollector() succeeded without a directory")
+	}
+	if _, err := NewXrdcopyCollector("test", opt, "/eos/test/probe", nil, 0); err == nil {
+		t.Error("NewXrdcopyCollector() succeeded without an interval")
+	}
+
+	// nothing is exported until the first probe finishes
+	c, err := NewXrdcopyCollector("test", opt, "/eos/test/probe", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(t, c); len(got) != 0 {
+		t.Errorf("collected %v before any probe", got)
+	}
+}
+
+func TestXrdcopyProbe(t *testing.T) {
+	runner := newStoreRunner("root://eos-test.example.org", nil)
+	got := probeSeries(t, context.Background(), xrdcopyOptions(t, runner), "default", "ssd")
+
+	for _, space := range []string{"default", "ssd"} {
+		checkSeries(t, got, map[string]float64{
+			`eos_xrdcopy_probe_success{cluster="test",space="` + space + `"}`: 1,
+		})
+		for _, phase := range []string{"write", "stat", "read", "delete"} {
+			if _, ok := got[`eos_xrdcopy_probe_duration_seconds{cluster="test",phase="`+phase+`",space="`+space+`"}`]; !ok {
+				t.Errorf("missing the %s duration of space %s", phase, space)
+			}
+		}
+		if ts := got[`eos_xrdcopy_probe_timestamp_seconds{cluster="test",space="`+space+`"}`]; ts <= 0 {
+			t.Errorf("timestamp of space %s = %v, want the start of the probe", space, ts)
+		}
+	}
+	if len(runner.files) != 0 {
+		t.Errorf("the probe left %d files behind", len(runner.files))
+	}
+}
+
+func TestXrdcopyProbeFailure(t *testing.T) {
+	// the write fails, nothing is deleted
+	hostname, _ := os.Hostname()
+	runner := eosclient.NewFakeRunner(map[string]eosclient.FakeResponse{
+		"root://eos-test.example.org//eos/test/probe/eos_exporter." + hostname + ".default?eos.ruid=0&eos.rgid=0&eos.space=default": {Stderr: "[ERROR] Server responded with an error", Status: 54},
+	})
+	got := probeSeries(t, context.Background(), xrdcopyOptions(t, runner))
+	checkSeries(t, got, map[string]float64{
+		`eos_xrdcopy_probe_success{cluster="test",space="default"}`: 0,
+	})
+	checkAbsent(t, got, `eos_xrdcopy_probe_duration_seconds{cluster="test",phase="stat"`, `eos_xrdcopy_probe_duration_seconds{cluster="test",phase="delete"`)
+	for _, call := range runner.Calls() {
+		if call[0] != "xrdcopy" {
+			t.Errorf("ran %q after a failed write", call)
+		}
+	}
+}
+
+func TestXrdcopyProbeCancelled(t *testing.T) {
+	// the probe runs out of time after the write, the file is deleted all the same
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner := newStoreRunner("root://eos-test.example.org", nil)
+	runner.uploaded = cancel
+
+	got := probeSeries(t, ctx, xrdcopyOptions(t, runner))
+	checkSeries(t, got, map[string]float64{
+		`eos_xrdcopy_probe_success{cluster="test",space="default"}`: 0,
+	})
+	checkAbsent(t, got, `eos_xrdcopy_probe_duration_seconds{cluster="test",phase="read"`)
+	if len(runner.files) != 0 {
+		t.Errorf("the probe left %d files behind", len(runner.files))
+	}
+}
+
+func TestXrdcopyUpdateFailure(t *testing.T) {
+	// the role cannot be resolved, the probe fails as a whole
+	opt := xrdcopyOptions(t, eosclient.NewFakeRunner(nil))
+	opt.RoleUser = "nosuchuser-eos-exporter"
+	opt.RoleGroup = ""
+	c, err := NewXrdcopyCollector("test", opt, "/eos/test/probe", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.run(context.Background())
+	ch := make(chan prometheus.Metric, 10)
+	if err := c.Update(context.Background(), ch); err == nil {
+		t.Error("Update() succeeded after a failed probe")
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of the file given with --config.file.
+// The settings it holds take precedence over the corresponding flags.
+type Config struct {
+	// Instance exported on the metrics path, used as the cluster label.
+	Instance string `yaml:"instance"`
+
+	// How to reach the instance exported on the metrics path.
+	InstanceConfig `yaml:",inline"`
+
+	// Location of the eos client binary.
+	EOSBinary string `yaml:"eos_binary"`
+
+	// Location of the xrdcopy binary.
+	XrdcopyBinary string `yaml:"xrdcopy_binary"`
+
+	// Time allowed to each eos command.
+	CommandTimeout time.Duration `yaml:"command_timeout"`
+
+	// Collectors enabled, for the metrics path and the probes.
+	Collectors []string `yaml:"collectors"`
+
+	// Time allowed to collect the metrics when the scraper does not announce its timeout,
+	// and offset subtracted from the one announced by Prometheus.
+	ScrapeTimeout       time.Duration `yaml:"scrape_timeout"`
+	ScrapeTimeoutOffset time.Duration `yaml:"scrape_timeout_offset"`
+
+	// Interval at which the instance exported on the metrics path is refreshed in the background.
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// Settings of the quota collector.
+	Quota QuotaConfig `yaml:"quota"`
+
+	// Settings of the fsck collector.
+	Fsck FsckConfig `yaml:"fsck"`
+
+	// Settings of the io collector.
+	IO IOConfig `yaml:"io"`
+
+	// Settings of the who collector.
+	Who WhoConfig `yaml:"who"`
+
+	// Settings of the ns_activity collector.
+	NSActivity NSActivityConfig `yaml:"ns_activity"`
+
+	// Settings of the xrdcopy collector.
+	Xrdcopy XrdcopyConfig `yaml:"xrdcopy"`
+
+	// Rewrites applied to the label values of the EOS metrics, in order.
+	LabelRewrites []*LabelRewrite `yaml:"label_rewrites"`
+
+	// Instances are the EOS instances that can be probed, by name.
+	Instances map[string]*InstanceConfig `yaml:"instances"`
+}
+
+// InstanceConfig describes how to reach an EOS instance.
+type InstanceConfig struct {
+	// URL of the MGM of the instance, e.g. root://eospps.cern.ch
+	MGMURL string `yaml:"mgm_url"`
+
+	// User and group (names or ids) whose role the eos commands are run with.
+	// Default to the --eos-role-user and --eos-role-group flags.
+	RoleUser  string `yaml:"role_user"`
+	RoleGroup string `yaml:"role_group"`
+
+	// sss keytab used to authenticate against the MGM.
+	Keytab string `yaml:"keytab"`
+}
+
+// QuotaConfig selects the quota nodes exported by the quota collector.
+type QuotaConfig struct {
+	// Regular expressions of the quota node paths to export, and not to export.
+	Include string `yaml:"include"`
+	Exclude string `yaml:"exclude"`
+}
+
+// FsckConfig tunes the fsck collector.
+type FsckConfig struct {
+	// Whether the errors of each filesystem are exported too.
+	PerFS *bool `yaml:"per_fs"`
+}
+
+// IOConfig tunes the io collector.
+type IOConfig struct {
+	// Whether the traffic of each uid and gid is exported too.
+	PerUser *bool `yaml:"per_user"`
+}
+
+// WhoConfig tunes the who collector.
+type WhoConfig struct {
+	// Number of users with the most sessions whose sessions are exported.
+	TopUsers *int `yaml:"top_users"`
+}
+
+// NSActivityConfig tunes the ns_activity collector.
+type NSActivityConfig struct {
+	// Number of uids, and of gids, whose activity is exported on its own.
+	TopUsers *int `yaml:"top_users"`
+}
+
+// XrdcopyConfig tunes the xrdcopy probe.
+type XrdcopyConfig struct {
+	// EOS directory where the probe files are written.
+	Path string `yaml:"path"`
+
+	// Spaces the probe files are written to.
+	Spaces []string `yaml:"spaces"`
+
+	// Minimum time between two probes.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// LabelRewrite replaces the value of a label when it fully matches a regular expression,
+// like a Prometheus relabeling rule. The replacement can refer to the groups of the regex as $1, $2...
+type LabelRewrite struct {
+	Label       string `yaml:"label"`
+	Regex       string `yaml:"regex"`
+	Replacement string `yaml:"replacement"`
+
+	re *regexp.Regexp
+}
+
+// loadConfig reads and validates the configuration file.
+func loadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.MGMURL != "" && !strings.HasPrefix(cfg.MGMURL, "root://") {
+		return fmt.Errorf("mgm_url %q is not a root:// URL", cfg.MGMURL)
+	}
+	if cfg.CommandTimeout < 0 || cfg.ScrapeTimeout < 0 || cfg.ScrapeTimeoutOffset < 0 || cfg.PollInterval < 0 || cfg.Xrdcopy.Interval < 0 {
+		return errors.New("durations must not be negative")
+	}
+	if cfg.Who.TopUsers != nil && *cfg.Who.TopUsers < 0 {
+		return errors.New("who top_users must not be negative")
+	}
+	if cfg.NSActivity.TopUsers != nil && *cfg.NSActivity.TopUsers < 0 {
+		return errors.New("ns_activity top_users must not be negative")
+	}
+	for _, name := range cfg.Collectors {
+		if _, ok := collectorFactories[name]; !ok {
+			return fmt.Errorf("unknown collector %q", name)
+		}
+	}
+
+	for i, rw := range cfg.LabelRewrites {
+		if rw == nil || rw.Label == "" {
+			return fmt.Errorf("label rewrite %d: label is required", i)
+		}
+		re, err := regexp.Compile("^(?:" + rw.Regex + ")$")
+		if err != nil {
+			return fmt.Errorf("label rewrite %d: %w", i, err)
+		}
+		rw.re = re
+	}
+
+	urls := make(map[string]string, len(cfg.Instances))
+	for name, inst := range cfg.Instances {
+		if name == "" {
+			return errors.New("empty instance name")
+		}
+		if inst == nil || inst.MGMURL == "" {
+			return fmt.Errorf("instance %s: mgm_url is required", name)
+		}
+		if !strings.HasPrefix(inst.MGMURL, "root://") {
+			return fmt.Errorf("instance %s: mgm_url %q is not a root:// URL", name, inst.MGMURL)
+		}
+		if other, ok := urls[inst.MGMURL]; ok {
+			return fmt.Errorf("instances %s and %s share the mgm_url %s", other, name, inst.MGMURL)
+		}
+		urls[inst.MGMURL] = name
+	}
+	return nil
+}
+
+// overrideRole replaces the role user and group by the given ones, when set.
+// The group of the replaced role does not apply to another user, so it is reset along with the user.
+func overrideRole(user, group *string, newUser, newGroup string) {
+	if newUser != "" {
+		*user, *group = newUser, ""
+	}
+	if newGroup != "" {
+		*group = newGroup
+	}
+}
+
+// apply returns a copy of opts overridden by the settings of the configuration.
+func (cfg *Config) apply(opts Options) *Options {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&opts.EOSInstance, cfg.Instance)
+	set(&opts.EOSMGMURL, cfg.MGMURL)
+	set(&opts.EOSKeytab, cfg.Keytab)
+	set(&opts.EOSBinary, cfg.EOSBinary)
+	set(&opts.XrdcopyBinary, cfg.XrdcopyBinary)
+	set(&opts.XrdcopyPath, cfg.Xrdcopy.Path)
+	set(&opts.QuotaInclude, cfg.Quota.Include)
+	set(&opts.QuotaExclude, cfg.Quota.Exclude)
+	overrideRole(&opts.EOSRoleUser, &opts.EOSRoleGroup, cfg.RoleUser, cfg.RoleGroup)
+
+	setDuration := func(dst *time.Duration, v time.Duration) {
+		if v != 0 {
+			*dst = v
+		}
+	}
+	setDuration(&opts.EOSCommandTimeout, cfg.CommandTimeout)
+	setDuration(&opts.ScrapeTimeout, cfg.ScrapeTimeout)
+	setDuration(&opts.ScrapeTimeoutOffset, cfg.ScrapeTimeoutOffset)
+	setDuration(&opts.PollInterval, cfg.PollInterval)
+	setDuration(&opts.XrdcopyInterval, cfg.Xrdcopy.Interval)
+
+	if cfg.Fsck.PerFS != nil {
+		opts.FsckPerFS = *cfg.Fsck.PerFS
+	}
+	if cfg.IO.PerUser != nil {
+		opts.IOPerUser = *cfg.IO.PerUser
+	}
+	if cfg.Who.TopUsers != nil {
+		opts.WhoTopUsers = *cfg.Who.TopUsers
+	}
+	if cfg.NSActivity.TopUsers != nil {
+		opts.NSActivityTopUsers = *cfg.NSActivity.TopUsers
+	}
+
+	if len(cfg.Xrdcopy.Spaces) > 0 {
+		opts.XrdcopySpaces = cfg.Xrdcopy.Spaces
+	}
+	if len(cfg.Collectors) > 0 {
+		opts.Collectors = cfg.Collectors
+	}
+	return &opts
+}
+
+// instance returns the configured instance matching target, either by name or by MGM URL.
+func (cfg *Config) instance(target string) (string, *InstanceConfig, bool) {
+	if inst, ok := cfg.Instances[target]; ok {
+		return target, inst, true
+	}
+	target = strings.TrimSuffix(target, "/")
+	for name, inst := range cfg.Instances {
+		if strings.TrimSuffix(inst.MGMURL, "/") == target {
+			return name, inst, true
+		}
+	}
+	return "", nil, false
+}
+
+// rewriteGatherer applies label rewrites to the metrics of a Gatherer.
+type rewriteGatherer struct {
+	prometheus.Gatherer
+	rewrites []*LabelRewrite
+}
+
+// Gather rewrites the label values of the gathered metrics.
+func (g rewriteGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := g.Gatherer.Gather()
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			for _, lp := range m.Label {
+				for _, rw := range g.rewrites {
+					if lp.GetName() != rw.Label {
+						continue
+					}
+					if match := rw.re.FindStringSubmatchIndex(lp.GetValue()); match != nil {
+						v := string(rw.re.ExpandString(nil, rw.Replacement, lp.GetValue(), match))
+						lp.Value = &v
+					}
+				}
+			}
+		}
+	}
+	return mfs, err
+}
@@ -15,17 +15,25 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	kitlog "github.com/go-kit/kit/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/log"
+	"github.com/prometheus/exporter-toolkit/web"
 	"gitlab.cern.ch/rvalverd/eos_exporter/collector"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
 
 	_ "embed"
 )
@@ -42,65 +50,534 @@ var (
 	goVersion string
 )
 
+var (
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("eos_exporter", "collector", "duration_seconds"),
+		"eos_exporter: Duration of a collector scrape.",
+		[]string{"collector"},
+		nil,
+	)
+	scrapeSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("eos_exporter", "collector", "success"),
+		"eos_exporter: Whether a collector succeeded.",
+		[]string{"collector"},
+		nil,
+	)
+	snapshotAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("eos_exporter", "snapshot", "age_seconds"),
+		"eos_exporter: Age of the last successful snapshot of a collector, when polling in the background.",
+		[]string{"collector"},
+		nil,
+	)
+)
+
 // EOSExporter wraps all the EOS collectors and provides a single global exporter to extracts metrics out of.
 type EOSExporter struct {
-	mu         sync.Mutex
-	collectors []prometheus.Collector
+	collectors map[string]collector.Collector
+
+	// snapshots holds the outcome of the last background refresh of each collector.
+	// It is nil unless the exporter is polling.
+	mu        sync.RWMutex
+	snapshots map[string]*snapshot
 }
 
-// Verify that the exporter implements the interface correctly.
-var _ prometheus.Collector = &EOSExporter{}
+// snapshot is the last successful set of metrics of a collector,
+// along with the outcome of its latest refresh.
+type snapshot struct {
+	metrics   []prometheus.Metric
+	timestamp time.Time
+	duration  time.Duration
+	err       error
+}
+
+// collectorFactory creates a collector of an instance, tuned with the options of the exporter.
+type collectorFactory struct {
+	// enabledByDefault tells whether the collector runs unless disabled with --no-collector.<name>.
+	enabledByDefault bool
+	new              func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error)
+}
 
-// NewEOSExporter creates an instance to EOSExporter
-func NewEOSExporter(instance string) *EOSExporter {
-	return &EOSExporter{
-		collectors: []prometheus.Collector{
-			collector.NewSpaceCollector(instance),      // eos space stats
-			collector.NewGroupCollector(instance),      // eos scheduling group stats
-			collector.NewNodeCollector(instance),       // eos node stats
-			collector.NewFSCollector(instance),         // eos filesystem stats
-			collector.NewVSCollector(instance),         // eos FST versions information
-			collector.NewNSCollector(instance),         // eos namespace information
-			collector.NewNSActivityCollector(instance), // eos namespace activity information
-		},
+// collectorFactories creates the available collectors, by name.
+var collectorFactories = map[string]collectorFactory{
+	// eos space stats
+	"space": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewSpaceCollector(cluster, opt), nil
+	}},
+	// eos scheduling group stats
+	"group": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewGroupCollector(cluster, opt), nil
+	}},
+	// eos node stats
+	"node": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewNodeCollector(cluster, opt), nil
+	}},
+	// eos filesystem stats
+	"fs": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewFSCollector(cluster, opt), nil
+	}},
+	// eos FST versions information
+	"vs": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewVSCollector(cluster, opt), nil
+	}},
+	// eos namespace information
+	"ns": {true, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewNSCollector(cluster, opt), nil
+	}},
+	// eos namespace activity information
+	"ns_activity": {true, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewNSActivityCollector(cluster, opt, o.NSActivityTopUsers), nil
+	}},
+	// eos quota nodes usage
+	"quota": {false, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewQuotaCollector(cluster, opt, o.QuotaInclude, o.QuotaExclude)
+	}},
+	// eos fsck errors and threads
+	"fsck": {false, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewFsckCollector(cluster, opt, o.FsckPerFS), nil
+	}},
+	// eos recycle bin usage
+	"recycle": {false, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewRecycleCollector(cluster, opt), nil
+	}},
+	// eos io statistics
+	"io": {false, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewIOCollector(cluster, opt, o.IOPerUser), nil
+	}},
+	// eos fusex clients
+	"fusex": {false, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewFusexCollector(cluster, opt), nil
+	}},
+	// eos client sessions
+	"who": {false, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewWhoCollector(cluster, opt, o.WhoTopUsers), nil
+	}},
+	// eos access rules
+	"access": {false, func(cluster string, opt *eosclient.Options, _ *Options) (collector.Collector, error) {
+		return collector.NewAccessCollector(cluster, opt), nil
+	}},
+	// end-to-end write and read probe
+	"xrdcopy": {false, func(cluster string, opt *eosclient.Options, o *Options) (collector.Collector, error) {
+		return collector.NewXrdcopyCollector(cluster, opt, o.XrdcopyPath, o.XrdcopySpaces, o.XrdcopyInterval)
+	}},
+}
+
+// collectorNames returns the names of the available collectors, sorted.
+func collectorNames() []string {
+	names := make([]string, 0, len(collectorFactories))
+	for name := range collectorFactories {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
-// Describe sends all the descriptors of the collectors included to the provided channel.
-func (c *EOSExporter) Describe(ch chan<- *prometheus.Desc) {
-	for _, cc := range c.collectors {
+// defaultCollectors returns the names of the collectors enabled by default, sorted.
+func defaultCollectors() []string {
+	names := []string{}
+	for _, name := range collectorNames() {
+		if collectorFactories[name].enabledByDefault {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NewEOSExporter creates an instance to EOSExporter running the collectors enabled in o,
+// or the default ones when none is given.
+func NewEOSExporter(instance string, opt *eosclient.Options, o *Options) (*EOSExporter, error) {
+	names := o.Collectors
+	if len(names) == 0 {
+		names = defaultCollectors()
+	}
+	collectors := make(map[string]collector.Collector, len(names))
+	for _, name := range names {
+		factory, ok := collectorFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+		cc, err := factory.new(instance, opt, o)
+		if err != nil {
+			return nil, fmt.Errorf("collector %s: %w", name, err)
+		}
+		collectors[name] = cc
+	}
+	return &EOSExporter{collectors: collectors}, nil
+}
+
+// only returns the subset of the collectors of the exporter with the given names.
+func (c *EOSExporter) only(names []string) (map[string]collector.Collector, error) {
+	collectors := make(map[string]collector.Collector, len(names))
+	for _, name := range names {
+		cc, ok := c.collectors[name]
+		if !ok {
+			return nil, fmt.Errorf("collector %q is unknown or disabled", name)
+		}
+		collectors[name] = cc
+	}
+	return collectors, nil
+}
+
+func describe(collectors map[string]collector.Collector, ch chan<- *prometheus.Desc) {
+	for _, cc := range collectors {
 		cc.Describe(ch)
 	}
 }
 
-// Collect sends the collected metrics from each of the collectors to prometheus.
-func (c *EOSExporter) Collect(ch chan<- prometheus.Metric) {
+// collect runs the given collectors in parallel and sends their metrics to ch.
+// The collectors still running when ctx is done are reported as failed and their metrics are dropped.
+func (c *EOSExporter) collect(ctx context.Context, collectors map[string]collector.Collector, ch chan<- prometheus.Metric) {
+	if c.collectSnapshots(collectors, ch) {
+		return
+	}
+	ctx = collector.WithSharedListings(ctx)
+
+	begin := time.Now()
+	results := make(chan scrapeResult, len(collectors))
+	pending := make(map[string]struct{}, len(collectors))
+	for name, cc := range collectors {
+		pending[name] = struct{}{}
+		go func(name string, cc collector.Collector) {
+			results <- execute(ctx, name, cc)
+		}(name, cc)
+	}
+
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			for _, m := range r.metrics {
+				ch <- m
+			}
+			ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, r.duration.Seconds(), r.name)
+			ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, r.success(), r.name)
+		case <-ctx.Done():
+			duration := time.Since(begin)
+			for name := range pending {
+				log.Errorf("collector %s did not finish within %fs: %s", name, duration.Seconds(), ctx.Err())
+				ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, duration.Seconds(), name)
+				ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, 0, name)
+			}
+			return
+		}
+	}
+}
+
+// Start starts the collectors running on their own schedule, until ctx is done.
+func (c *EOSExporter) Start(ctx context.Context) {
+	for _, cc := range c.collectors {
+		if bc, ok := cc.(collector.BackgroundCollector); ok {
+			bc.Start(ctx)
+		}
+	}
+}
+
+// withoutBackground removes the collectors running on their own schedule from the exporter.
+func (c *EOSExporter) withoutBackground() {
+	for name, cc := range c.collectors {
+		if _, ok := cc.(collector.BackgroundCollector); ok {
+			delete(c.collectors, name)
+		}
+	}
+}
+
+// Poll refreshes the collectors in the background every interval, until ctx is done.
+// From then on, Collect serves the last successful snapshot of every collector
+// instead of running it, so the load on the MGM does not depend on the number of scrapers.
+func (c *EOSExporter) Poll(ctx context.Context, interval time.Duration) {
+	c.mu.Lock()
+	c.snapshots = make(map[string]*snapshot, len(c.collectors))
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.refresh(ctx, interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refresh runs all the collectors in parallel and stores their outcome.
+// Each refresh has to finish within timeout.
+func (c *EOSExporter) refresh(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = collector.WithSharedListings(ctx)
+
+	var wg sync.WaitGroup
+	for name, cc := range c.collectors {
+		wg.Add(1)
+		go func(name string, cc collector.Collector) {
+			defer wg.Done()
+			c.store(execute(ctx, name, cc))
+		}(name, cc)
+	}
+	wg.Wait()
+}
+
+// store records the outcome of a collector run, keeping the previous metrics if it failed.
+func (c *EOSExporter) store(r scrapeResult) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for _, cc := range c.collectors {
-		cc.Collect(ch)
+	s, ok := c.snapshots[r.name]
+	if !ok {
+		s = &snapshot{}
+		c.snapshots[r.name] = s
+	}
+	s.duration, s.err = r.duration, r.err
+	if r.err == nil {
+		s.metrics, s.timestamp = r.metrics, time.Now()
+	}
+}
+
+// collectSnapshots sends the stored snapshots of the given collectors to ch.
+// It returns false when the exporter is not polling.
+func (c *EOSExporter) collectSnapshots(collectors map[string]collector.Collector, ch chan<- prometheus.Metric) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.snapshots == nil {
+		return false
 	}
+	for name, s := range c.snapshots {
+		if _, ok := collectors[name]; !ok {
+			continue
+		}
+		for _, m := range s.metrics {
+			ch <- m
+		}
+		success := 1.0
+		if s.err != nil {
+			success = 0
+		}
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, s.duration.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, success, name)
+		if !s.timestamp.IsZero() {
+			ch <- prometheus.MustNewConstMetric(snapshotAgeDesc, prometheus.GaugeValue, time.Since(s.timestamp).Seconds(), name)
+		}
+	}
+	return true
+}
+
+// scrapeResult holds the outcome of a single collector run.
+type scrapeResult struct {
+	name     string
+	metrics  []prometheus.Metric
+	duration time.Duration
+	err      error
+}
+
+func (r scrapeResult) success() float64 {
+	if r.err != nil {
+		return 0
+	}
+	return 1
+}
+
+// execute runs a single collector, buffering its metrics, and reports how long it took and whether it succeeded.
+func execute(ctx context.Context, name string, c collector.Collector) scrapeResult {
+	r := scrapeResult{name: name}
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	go func() {
+		for m := range ch {
+			r.metrics = append(r.metrics, m)
+		}
+		close(done)
+	}()
+
+	begin := time.Now()
+	r.err = c.Update(ctx, ch)
+	r.duration = time.Since(begin)
+	close(ch)
+	<-done
+
+	if r.err != nil {
+		log.Errorf("collector %s failed after %fs: %s", name, r.duration.Seconds(), r.err)
+		r.metrics = nil
+	}
+	return r
+}
+
+// scrape binds a collection of some of the collectors of the exporter to the context of a single scrape.
+type scrape struct {
+	exporter   *EOSExporter
+	collectors map[string]collector.Collector
+	ctx        context.Context
+}
+
+// Describe sends the descriptors of the scraped collectors.
+func (s scrape) Describe(ch chan<- *prometheus.Desc) {
+	ch <- scrapeDurationDesc
+	ch <- scrapeSuccessDesc
+	ch <- snapshotAgeDesc
+	describe(s.collectors, ch)
+}
+
+// Collect sends the metrics of the collectors that finish before the scrape deadline.
+func (s scrape) Collect(ch chan<- prometheus.Metric) {
+	s.exporter.collect(s.ctx, s.collectors, ch)
+}
+
+// scrapeTimeout returns the time left to answer the scrape, derived from the
+// timeout Prometheus announces minus a safety offset.
+func scrapeTimeout(r *http.Request, opts *Options) time.Duration {
+	v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds")
+	if v == "" {
+		return opts.ScrapeTimeout
+	}
+	seconds, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Errorf("invalid X-Prometheus-Scrape-Timeout-Seconds header %q: %s", v, err)
+		return opts.ScrapeTimeout
+	}
+	timeout := time.Duration(seconds*float64(time.Second)) - opts.ScrapeTimeoutOffset
+	if timeout <= 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return timeout
+}
+
+// metricsHandler serves the metrics of the exporter in use along with the ones of the process.
+func metricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := currentState()
+		if s.exporter == nil {
+			promhttp.Handler().ServeHTTP(w, r)
+			return
+		}
+		serveMetrics(w, r, s, s.exporter, prometheus.DefaultGatherer)
+	})
+}
+
+// serveMetrics serves the metrics of the exporter, collected within the scrape deadline of s,
+// followed by the ones of the extra gatherers.
+// The collectors can be restricted with collect[] query parameters.
+func serveMetrics(w http.ResponseWriter, r *http.Request, s *state, exporter *EOSExporter, extra ...prometheus.Gatherer) {
+	collectors := exporter.collectors
+	if names := r.URL.Query()["collect[]"]; len(names) > 0 {
+		var err error
+		collectors, err = exporter.only(names)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout(r, s.opts))
+	defer cancel()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(scrape{exporter, collectors, ctx})
+	gatherers := append(prometheus.Gatherers{rewriteGatherer{registry, s.cfg.LabelRewrites}}, extra...)
+	promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}).ServeHTTP(w, r)
 }
 
 type Options struct {
-	ListenAddress string
-	MetricsPath   string
-	EOSInstance   string
-	Version       bool
-	Help          bool
+	ListenAddress       string
+	WebConfigFile       string
+	MetricsPath         string
+	EOSInstance         string
+	ConfigFile          string
+	EOSMGMURL           string
+	EOSBinary           string
+	XrdcopyBinary       string
+	EOSRoleUser         string
+	EOSRoleGroup        string
+	EOSKeytab           string
+	EOSCommandTimeout   time.Duration
+	ScrapeTimeout       time.Duration
+	ScrapeTimeoutOffset time.Duration
+	PollInterval        time.Duration
+	Collectors          []string
+	QuotaInclude        string
+	QuotaExclude        string
+	FsckPerFS           bool
+	IOPerUser           bool
+	WhoTopUsers         int
+	NSActivityTopUsers  int
+	XrdcopyPath         string
+	XrdcopySpaces       []string
+	XrdcopyInterval     time.Duration
+	Version             bool
+	Help                bool
 }
 
 var cmdOptions *Options = &Options{}
 
+// commaList is a flag holding a comma separated list.
+type commaList struct {
+	list *[]string
+}
+
+func (l commaList) String() string {
+	if l.list == nil {
+		return ""
+	}
+	return strings.Join(*l.list, ",")
+}
+
+func (l commaList) Set(v string) error {
+	*l.list = nil
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l.list = append(*l.list, item)
+		}
+	}
+	return nil
+}
+
+// collectorFlags holds the --collector.<name> and --no-collector.<name> flags of each collector.
+var collectorFlags = map[string][2]*bool{}
+
 func init() {
 	flag.StringVar(&cmdOptions.ListenAddress, "listen-address", ":9373", "Address on which to expose metrics and web interface.")
+	flag.StringVar(&cmdOptions.WebConfigFile, "web.config.file", "", "Path to a configuration file enabling TLS or basic authentication, in the format of the Prometheus exporter-toolkit.")
 	flag.StringVar(&cmdOptions.MetricsPath, "telemetry-path", "/metrics", "Path under which to expose metrics.")
-	flag.StringVar(&cmdOptions.EOSInstance, "eos-instance", "", "EOS instance name.")
+	flag.StringVar(&cmdOptions.EOSInstance, "eos-instance", "", "EOS instance name, exported as the cluster label.")
+	flag.StringVar(&cmdOptions.ConfigFile, "config.file", "", "Configuration file, overriding the flags. It lists the instances that can be probed on /probe?target=<instance-or-mgm-url> and is reloaded on SIGHUP or POST /-/reload.")
+	flag.StringVar(&cmdOptions.EOSMGMURL, "eos-mgm-url", os.Getenv("EOS_MGM_URL"), "URL of the EOS MGM, e.g. root://eos-mgm.example.org. Defaults to $EOS_MGM_URL, or to the instance found in /etc/sysconfig/eos_env.")
+	flag.StringVar(&cmdOptions.EOSBinary, "eos-binary", "/usr/bin/eos", "Location of the eos client binary.")
+	flag.StringVar(&cmdOptions.XrdcopyBinary, "xrdcopy-binary", "/usr/bin/xrdcopy", "Location of the xrdcopy binary.")
+	flag.StringVar(&cmdOptions.EOSRoleUser, "eos-role-user", "root", "User (name or uid) whose role the eos commands are run with.")
+	flag.StringVar(&cmdOptions.EOSRoleGroup, "eos-role-group", "", "Group (name or gid) whose role the eos commands are run with. Defaults to the primary group of the role user.")
+	flag.StringVar(&cmdOptions.EOSKeytab, "eos-keytab", "", "sss keytab used to authenticate against the MGM. Defaults to the credentials available to the process.")
+	flag.DurationVar(&cmdOptions.EOSCommandTimeout, "eos-command-timeout", 10*time.Second, "Time allowed to each eos command.")
+	flag.DurationVar(&cmdOptions.ScrapeTimeout, "scrape-timeout", 10*time.Second, "Time allowed to collect the metrics when the scraper does not announce its timeout.")
+	flag.DurationVar(&cmdOptions.ScrapeTimeoutOffset, "scrape-timeout-offset", 500*time.Millisecond, "Offset subtracted from the timeout announced by Prometheus, to leave time to send the response.")
+	flag.DurationVar(&cmdOptions.PollInterval, "poll-interval", 0, "Refresh the EOS listings in the background at this interval and serve the last successful snapshot on scrape. Disabled when 0.")
+	for _, name := range collectorNames() {
+		collectorFlags[name] = [2]*bool{
+			flag.Bool("collector."+name, collectorFactories[name].enabledByDefault, fmt.Sprintf("Enable the %s collector.", name)),
+			flag.Bool("no-collector."+name, false, fmt.Sprintf("Disable the %s collector.", name)),
+		}
+	}
+	flag.StringVar(&cmdOptions.QuotaInclude, "collector.quota.include", "", "Regular expression of the quota node paths to export. All by default.")
+	flag.StringVar(&cmdOptions.QuotaExclude, "collector.quota.exclude", "", "Regular expression of the quota node paths not to export.")
+	flag.BoolVar(&cmdOptions.FsckPerFS, "collector.fsck.per-fs", false, "Also export the fsck errors of each filesystem.")
+	flag.BoolVar(&cmdOptions.IOPerUser, "collector.io.per-user", false, "Also export the io traffic of each uid and gid.")
+	flag.IntVar(&cmdOptions.WhoTopUsers, "collector.who.top-users", 0, "Number of users with the most sessions whose sessions are exported. None when 0.")
+	flag.IntVar(&cmdOptions.NSActivityTopUsers, "collector.ns_activity.top-users", 0, "Number of uids, and of gids, with the highest rate over the last minute whose namespace activity is exported, the others are summed as \"other\". None when 0.")
+	flag.StringVar(&cmdOptions.XrdcopyPath, "collector.xrdcopy.path", "", "EOS directory where the xrdcopy probe writes its files. Required by the xrdcopy collector.")
+	flag.Var(commaList{&cmdOptions.XrdcopySpaces}, "collector.xrdcopy.spaces", "Comma separated spaces the xrdcopy probe writes to. Defaults to the default space.")
+	flag.DurationVar(&cmdOptions.XrdcopyInterval, "collector.xrdcopy.interval", time.Minute, "Time between two xrdcopy probes, run in the background. Scrapes are served the last results.")
 	flag.BoolVar(&cmdOptions.Help, "help", false, "Show the help and exit.")
 	flag.BoolVar(&cmdOptions.Version, "version", false, "Show the version and exit.")
 	flag.Parse()
 
+	for _, name := range collectorNames() {
+		if f := collectorFlags[name]; *f[0] && !*f[1] {
+			cmdOptions.Collectors = append(cmdOptions.Collectors, name)
+		}
+	}
+
 	err := validate()
 	if err != nil {
 		fmt.Printf("Error: %s\n", err.Error())
@@ -116,9 +593,13 @@ func validate() error {
 		return nil
 	}
 
-	// EOSInstamce is required
-	if cmdOptions.EOSInstance == "" {
-		return errors.New("Specify an EOS instance using the -eos-instance flag")
+	// EOSInstamce is required, unless the instances are probed
+	if cmdOptions.EOSInstance == "" && cmdOptions.ConfigFile == "" {
+		return errors.New("Specify an EOS instance using the -eos-instance flag, or the instances to probe using the -config.file flag")
+	}
+
+	if len(cmdOptions.Collectors) == 0 {
+		return errors.New("All the collectors are disabled")
 	}
 
 	return nil
@@ -150,10 +631,25 @@ func main() {
 		printVersion()
 	}
 
-	fmt.Printf("Starting eos exporter for instance: %s", cmdOptions.EOSInstance)
-	prometheus.Register(NewEOSExporter(cmdOptions.EOSInstance))
+	s, err := newState(cmdOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.start()
+	configReloadSuccess.Set(1)
+	configReloadSeconds.SetToCurrentTime()
+	reloadOnSignal()
+
+	if s.exporter != nil {
+		fmt.Printf("Starting eos exporter for instance: %s", s.opts.EOSInstance)
+	}
+	if len(s.cfg.Instances) > 0 {
+		log.Infof("Loaded %d instances to probe from %s", len(s.cfg.Instances), cmdOptions.ConfigFile)
+	}
 
-	http.Handle(cmdOptions.MetricsPath, promhttp.Handler())
+	http.Handle(cmdOptions.MetricsPath, metricsHandler())
+	http.Handle("/probe", probeHandler())
+	http.HandleFunc("/-/reload", reloadHandler)
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
 			<head><title>EOS Exporter</title></head>
@@ -164,8 +660,10 @@ func main() {
 			</html>`))
 	})
 
+	log.Infoln("Enabled collectors:", strings.Join(s.opts.Collectors, ", "))
 	log.Infoln("Listening on", cmdOptions.ListenAddress)
-	err := http.ListenAndServe(cmdOptions.ListenAddress, nil)
+	server := &http.Server{Addr: cmdOptions.ListenAddress}
+	err = web.ListenAndServe(server, cmdOptions.WebConfigFile, kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(os.Stderr)))
 	if err != nil {
 		log.Fatal(err)
 	}
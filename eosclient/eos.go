@@ -4,17 +4,15 @@ package eosclient
 // This code can be vastly improved.
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
-	"os/exec"
 	osuser "os/user"
 	"strconv"
 	"strings"
-	"syscall"
 	"unicode"
 
 	// "github.com/cernbox/reva/api"
@@ -35,6 +33,20 @@ type Options struct {
 	// URL of the EOS MGM. Default is root://eos-test.org
 	URL string
 
+	// User (name or uid) whose role the commands are run with. Default is root.
+	RoleUser string
+
+	// Group (name or gid) whose role the commands are run with.
+	// Defaults to the primary group of RoleUser, required when RoleUser is a uid unknown locally.
+	RoleGroup string
+
+	// sss keytab used to authenticate against the MGM.
+	// Defaults to the credentials available to the process.
+	Keytab string
+
+	// Time allowed to each eos command. Default is 10 seconds.
+	CommandTimeout time.Duration
+
 	// Location on the local fs where to store reads. Defaults to os.TempDir()
 	CacheDirectory string
 
@@ -43,6 +55,9 @@ type Options struct {
 
 	// Logger to use
 	Logger *zap.Logger
+
+	// Runner used to execute the commands. Defaults to ExecRunner.
+	Runner Runner
 }
 
 func (opt *Options) init() {
@@ -58,6 +73,14 @@ func (opt *Options) init() {
 		opt.URL = "root://eos-example.org"
 	}
 
+	if opt.RoleUser == "" {
+		opt.RoleUser = "root"
+	}
+
+	if opt.CommandTimeout == 0 {
+		opt.CommandTimeout = cmdTimeout
+	}
+
 	if opt.CacheDirectory == "" {
 		opt.CacheDirectory = os.TempDir()
 	}
@@ -66,12 +89,19 @@ func (opt *Options) init() {
 		l, _ := zap.NewProduction()
 		opt.Logger = l
 	}
+
+	if opt.Runner == nil {
+		opt.Runner = ExecRunner{}
+	}
 }
 
 // Client performs actions against a EOS management node (MGM).
 // It requires the eos-client and xrootd-client packages installed to work.
 type Client struct {
 	opt *Options
+
+	// uid and gid of the role the commands are run with.
+	uid, gid string
 }
 
 type NodeInfo struct {
@@ -89,6 +119,12 @@ type NodeInfo struct {
 	CfgStatSysThreads     string
 	SumStatNetInratemib   string
 	SumStatNetOutratemib  string
+	HeartbeatDelta        string
+	CfgStatus             string
+	CfgStatGeotag         string
+	CfgTxgw               string
+	CfgGwNtx              string
+	CfgGwRate             string
 }
 
 type SpaceInfo struct {
@@ -264,6 +300,82 @@ type NSActivityInfo struct {
 	Max        string
 }
 
+type QuotaInfo struct {
+	Space               string
+	Uid                 string
+	Gid                 string
+	UsedBytes           string
+	UsedLogicalBytes    string
+	UsedFiles           string
+	MaxBytes            string
+	MaxLogicalBytes     string
+	MaxFiles            string
+	PercentageUsedBytes string
+	StatusBytes         string
+	StatusFiles         string
+}
+
+type FsckReportInfo struct {
+	Timestamp string
+	Fsid      string
+	Tag       string
+	Count     string
+}
+
+type FsckStatInfo struct {
+	CollectionThread string
+	RepairThread     string
+}
+
+type RecycleInfo struct {
+	Path        string
+	UsedBytes   string
+	MaxBytes    string
+	VolumeUsage string
+	InodeUsage  string
+	Lifetime    string
+	Ratio       string
+}
+
+type IOStatInfo struct {
+	Uid         string
+	Gid         string
+	Application string
+	Domain      string
+	Measurement string
+	Total       string
+}
+
+type FusexInfo struct {
+	Host    string
+	Uuid    string
+	Version string
+	State   string
+	Mount   string
+	Caps    string
+	Delta   string
+}
+
+type WhoInfo struct {
+	Client   string
+	Uid      string
+	Auth     string
+	Protocol string
+	App      string
+}
+
+type AccessInfo struct {
+	Key   string
+	Value string
+}
+
+type FileInfo struct {
+	Path   string
+	Size   string
+	XSType string
+	XS     string
+}
+
 type Sys struct {
 	Eos struct {
 		Start   string `json:"start"`
@@ -301,108 +413,137 @@ type NodeLSResponse struct {
 
 func New(opt *Options) (*Client, error) {
 	opt.init()
+	uid, gid, err := getRole(opt.RoleUser, opt.RoleGroup)
+	if err != nil {
+		return nil, err
+	}
 	c := new(Client)
 	c.opt = opt
+	c.uid, c.gid = uid, gid
 	return c, nil
 }
 
-func getUnixUser(username string) (*osuser.User, error) {
-	return osuser.Lookup(username)
+// getRole resolves the role user and group into the uid and gid passed to "eos -r".
+// Numeric ids are used as they are, so identities only known to the MGM can be mapped,
+// but then the group has to be given when the uid is unknown locally.
+func getRole(user, group string) (string, string, error) {
+	var uid, gid string
+	if _, err := strconv.Atoi(user); err == nil {
+		uid = user
+		if group == "" {
+			u, err := osuser.LookupId(user)
+			if err != nil {
+				return "", "", fmt.Errorf("no primary group for the role user %s, a role group is required: %w", user, err)
+			}
+			gid = u.Gid
+		}
+	} else {
+		u, err := osuser.Lookup(user)
+		if err != nil {
+			return "", "", err
+		}
+		uid, gid = u.Uid, u.Gid
+	}
+
+	if group == "" {
+		return uid, gid, nil
+	}
+	if _, err := strconv.Atoi(group); err == nil {
+		return uid, group, nil
+	}
+	g, err := osuser.LookupGroup(group)
+	if err != nil {
+		return "", "", err
+	}
+	return uid, g.Gid, nil
 }
 
-// exec executes the command and returns the stdout, stderr and return code
-func (c *Client) execute(cmd *exec.Cmd) (string, string, error) {
-	cmd.Env = []string{
+// execute runs the command through the configured Runner and returns the stdout, stderr and error
+func (c *Client) execute(ctx context.Context, name string, args ...string) (string, string, error) {
+	env := []string{
 		"EOS_MGM_URL=" + c.opt.URL,
 	}
+	if c.opt.Keytab != "" {
+		env = append(env, "XrdSecPROTOCOL=sss", "XrdSecSSSKT="+c.opt.Keytab)
+	}
 
-	outBuf := &bytes.Buffer{}
-	errBuf := &bytes.Buffer{}
-	cmd.Stdout = outBuf
-	cmd.Stderr = errBuf
-	err := cmd.Run()
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	stdout, stderr, status, err := c.opt.Runner.Run(ctx, env, name, args...)
 	if c.opt.EnableLogging {
-		c.opt.Logger.Info("eosclient", zap.String("cmd", fmt.Sprintf("%+v", cmd)))
-	}
-
-	if exiterr, ok := err.(*exec.ExitError); ok {
-		// The program has exited with an exit code != 0
-		// This works on both Unix and Windows. Although package
-		// syscall is generally platform dependent, WaitStatus is
-		// defined for both Unix and Windows and in both cases has
-		// an ExitStatus() method with the same signature.
-		if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
-			switch status.ExitStatus() {
-			case 2:
-				err = fmt.Errorf("error: FIXME") // api.NewError(api.StorageNotFoundErrorCode)
-			}
-		}
+		c.opt.Logger.Info("eosclient", zap.String("cmd", cmd), zap.Int("status", status))
 	}
-	return outBuf.String(), errBuf.String(), err
-}
 
-// List the nodes on the instance
-func (c *Client) ListNode(ctx context.Context, username string) ([]*NodeInfo, error) {
-	unixUser, err := getUnixUser(username)
-	if err != nil {
-		return nil, err
+	if err == nil && status == 0 {
+		return stdout, stderr, nil
+	}
+	switch {
+	case ctx.Err() != nil:
+		err = fmt.Errorf("%s: %w", cmd, ctx.Err())
+	case status != 0:
+		err = fmt.Errorf("%s: exit status %d: %s", cmd, status, trimStderr(stderr))
+	default:
+		err = fmt.Errorf("%s: %w", cmd, err)
 	}
-	var (
-		ctxWt  context.Context
-		cancel context.CancelFunc
-	)
+	return stdout, stderr, err
+}
 
-	ctxWt, cancel = context.WithTimeout(ctx, cmdTimeout)
-	defer cancel()
+// maxStderr is the length of the stderr kept in the errors of execute.
+const maxStderr = 512
 
-	cmd := exec.CommandContext(ctxWt, "/usr/bin/eos", "-r", unixUser.Uid, unixUser.Gid, "node", "ls", "-m")
-	stdout, _, err := c.execute(cmd)
-	if err != nil {
-		return nil, err
+// trimStderr returns stderr on a single line, cut to maxStderr bytes.
+func trimStderr(stderr string) string {
+	stderr = strings.Join(strings.Fields(stderr), " ")
+	if len(stderr) > maxStderr {
+		stderr = stderr[:maxStderr] + "..."
 	}
-	return c.parseNodesInfo(stdout)
+	return stderr
 }
 
-// List the spaces on the instance
-func (c *Client) ListSpace(ctx context.Context, username string) ([]*SpaceInfo, error) {
-	unixUser, err := getUnixUser(username)
-	if err != nil {
-		return nil, err
-	}
+// eos runs an eos subcommand against the configured MGM with the configured role.
+// Every call gets its own CommandTimeout.
+func (c *Client) eos(ctx context.Context, args ...string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opt.CommandTimeout)
+	defer cancel()
 
-	var (
-		ctxWt  context.Context
-		cancel context.CancelFunc
-	)
+	return c.execute(ctx, c.opt.EosBinary, append([]string{"-r", c.uid, c.gid}, args...)...)
+}
 
-	ctxWt, cancel = context.WithTimeout(ctx, cmdTimeout)
+// xrdcopy copies src to dst, overwriting dst. Every call gets its own CommandTimeout.
+func (c *Client) xrdcopy(ctx context.Context, src, dst string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.opt.CommandTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctxWt, "/usr/bin/eos", "-r", unixUser.Uid, unixUser.Gid, "space", "ls", "-m")
-	stdout, _, err := c.execute(cmd)
+	_, _, err := c.execute(ctx, c.opt.XrdcopyBinary, "-f", "-s", src, dst)
+	return err
+}
+
+// xrootdURL returns the URL of path on the MGM, with the role and the extra opaque settings.
+func (c *Client) xrootdURL(path string, opaque ...string) string {
+	cgi := append([]string{"eos.ruid=" + c.uid, "eos.rgid=" + c.gid}, opaque...)
+	return strings.TrimSuffix(c.opt.URL, "/") + "//" + strings.TrimPrefix(path, "/") + "?" + strings.Join(cgi, "&")
+}
+
+// List the nodes on the instance
+func (c *Client) ListNode(ctx context.Context) ([]*NodeInfo, error) {
+	stdout, _, err := c.eos(ctx, "node", "ls", "-m")
 	if err != nil {
 		return nil, err
 	}
-	return c.parseSpacesInfo(stdout)
+	return c.parseNodesInfo(stdout)
 }
 
-// List the scheduling groups on the instance
-func (c *Client) ListGroup(ctx context.Context, username string) ([]*GroupInfo, error) {
-	unixUser, err := getUnixUser(username)
+// List the spaces on the instance
+func (c *Client) ListSpace(ctx context.Context) ([]*SpaceInfo, error) {
+	stdout, _, err := c.eos(ctx, "space", "ls", "-m")
 	if err != nil {
 		return nil, err
 	}
+	return c.parseSpacesInfo(stdout)
+}
 
-	var (
-		ctxWt  context.Context
-		cancel context.CancelFunc
-	)
-
-	ctxWt, cancel = context.WithTimeout(ctx, cmdTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctxWt, "/usr/bin/eos", "-r", unixUser.Uid, unixUser.Gid, "group", "ls", "-m")
-	stdout, _, err := c.execute(cmd)
+// List the scheduling groups on the instance
+func (c *Client) ListGroup(ctx context.Context) ([]*GroupInfo, error) {
+	stdout, _, err := c.eos(ctx, "group", "ls", "-m")
 	if err != nil {
 		return nil, err
 	}
@@ -410,22 +551,8 @@ func (c *Client) ListGroup(ctx context.Context, username string) ([]*GroupInfo,
 }
 
 // List the filesystems on the instance
-func (c *Client) ListFS(ctx context.Context, username string) ([]*FSInfo, error) {
-	unixUser, err := getUnixUser(username)
-	if err != nil {
-		return nil, err
-	}
-
-	var (
-		ctxWt  context.Context
-		cancel context.CancelFunc
-	)
-
-	ctxWt, cancel = context.WithTimeout(ctx, cmdTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctxWt, "/usr/bin/eos", "-r", unixUser.Uid, unixUser.Gid, "fs", "ls", "-m")
-	stdout, _, err := c.execute(cmd)
+func (c *Client) ListFS(ctx context.Context) ([]*FSInfo, error) {
+	stdout, _, err := c.eos(ctx, "fs", "ls", "-m")
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +560,7 @@ func (c *Client) ListFS(ctx context.Context, username string) ([]*FSInfo, error)
 }
 
 func (c *Client) getEosMGMVersion(ctx context.Context) (string, error) {
-	out, _, err := c.execute(exec.CommandContext(ctx, "/usr/bin/eos", "version"))
+	out, _, err := c.eos(ctx, "version")
 	if err != nil {
 		return "", err
 	}
@@ -449,17 +576,12 @@ func (c *Client) getEosMGMVersion(ctx context.Context) (string, error) {
 
 // List the version of different nodes in the instance
 func (c *Client) ListVS(ctx context.Context) ([]*VSInfo, error) {
-
-	ctx, cancel := context.WithTimeout(ctx, cmdTimeout)
-	defer cancel()
-
 	mgmVersion, err := c.getEosMGMVersion(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	//cmd = exec.CommandContext(ctxWt, "/usr/bin/eos", "-r", unixUser.Uid, unixUser.Gid, "-b", "node", "ls","-m", "--sys", "|", "grep", "cern.ch", "|", "sort", "-t:", "-uk1,1")
-	stdout, _, err := c.execute(exec.CommandContext(ctx, "/usr/bin/eos", "--json", "node", "ls"))
+	stdout, _, err := c.eos(ctx, "--json", "node", "ls")
 	if err != nil {
 		return nil, err
 	}
@@ -475,11 +597,7 @@ func (c *Client) ListVS(ctx context.Context) ([]*VSInfo, error) {
 
 // List the activity of different users in the instance
 func (c *Client) ListNS(ctx context.Context) ([]*NSInfo, []*NSActivityInfo, error) {
-
-	ctx, cancel := context.WithTimeout(ctx, cmdTimeout)
-	defer cancel()
-
-	stdout, _, err := c.execute(exec.CommandContext(ctx, "/usr/bin/eos", "ns", "stat", "-a", "-m"))
+	stdout, _, err := c.eos(ctx, "ns", "stat", "-a", "-m")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -487,6 +605,134 @@ func (c *Client) ListNS(ctx context.Context) ([]*NSInfo, []*NSActivityInfo, erro
 	return c.parseNSsInfo(stdout)
 }
 
+// List the user, group and project quotas of all the quota nodes
+func (c *Client) ListQuota(ctx context.Context) ([]*QuotaInfo, error) {
+	stdout, _, err := c.eos(ctx, "quota", "ls", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseQuotasInfo(stdout)
+}
+
+// Report the number of files in each fsck error class, instance-wide or per filesystem
+func (c *Client) FsckReport(ctx context.Context, perFS bool) ([]*FsckReportInfo, error) {
+	args := []string{"fsck", "report", "-m"}
+	if perFS {
+		args = []string{"fsck", "report", "-a", "-m"}
+	}
+	stdout, _, err := c.eos(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseFsckReportsInfo(stdout)
+}
+
+// Status of the fsck collection and repair threads
+func (c *Client) FsckStat(ctx context.Context) (*FsckStatInfo, error) {
+	stdout, _, err := c.eos(ctx, "fsck", "stat")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseFsckStatInfo(stdout)
+}
+
+// Usage and policy of the recycle bin
+func (c *Client) ListRecycle(ctx context.Context) (*RecycleInfo, error) {
+	stdout, _, err := c.eos(ctx, "recycle", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseRecycleInfo(stdout)
+}
+
+// IO statistics summed over all the users, and per uid and gid when all is set
+func (c *Client) IOStat(ctx context.Context, all bool) ([]*IOStatInfo, error) {
+	args := []string{"io", "stat", "-m"}
+	if all {
+		args = []string{"io", "stat", "-a", "-m"}
+	}
+	stdout, _, err := c.eos(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseIOStatsInfo(stdout)
+}
+
+// IO statistics per application tag and client domain
+func (c *Client) IOStatDetails(ctx context.Context) ([]*IOStatInfo, error) {
+	stdout, _, err := c.eos(ctx, "io", "stat", "-x", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseIOStatsInfo(stdout)
+}
+
+// Clients connected through eosxd
+func (c *Client) ListFusex(ctx context.Context) ([]*FusexInfo, error) {
+	stdout, _, err := c.eos(ctx, "fusex", "ls", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseFusexsInfo(stdout)
+}
+
+// Client sessions connected to the MGM
+func (c *Client) ListWho(ctx context.Context) ([]*WhoInfo, error) {
+	stdout, _, err := c.eos(ctx, "who", "-a", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseWhosInfo(stdout)
+}
+
+// Access rules: bans, allows, stalls, redirections and limits
+func (c *Client) ListAccess(ctx context.Context) ([]*AccessInfo, error) {
+	stdout, _, err := c.eos(ctx, "access", "ls", "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseAccessInfo(stdout)
+}
+
+// Upload copies the local file to path, on the given space when not empty
+func (c *Client) Upload(ctx context.Context, local, path, space string) error {
+	var opaque []string
+	if space != "" {
+		opaque = append(opaque, "eos.space="+space)
+	}
+	return c.xrdcopy(ctx, local, c.xrootdURL(path, opaque...))
+}
+
+// Download copies path to a new file of the cache directory and returns its location.
+// The caller has to remove it.
+func (c *Client) Download(ctx context.Context, path string) (string, error) {
+	f, err := ioutil.TempFile(c.opt.CacheDirectory, "eos_exporter-")
+	if err != nil {
+		return "", err
+	}
+	f.Close()
+	if err := c.xrdcopy(ctx, c.xrootdURL(path), f.Name()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// Size and checksum of a file
+func (c *Client) FileInfo(ctx context.Context, path string) (*FileInfo, error) {
+	stdout, _, err := c.eos(ctx, "file", "info", path, "-m")
+	if err != nil {
+		return nil, err
+	}
+	return c.parseFileInfo(stdout)
+}
+
+// Remove deletes a file
+func (c *Client) Remove(ctx context.Context, path string) error {
+	_, _, err := c.eos(ctx, "rm", path)
+	return err
+}
+
 func getHostname(hostport string) (string, string) {
 	split := strings.Split(hostport, ":")
 	return split[0], split[1]
@@ -517,7 +763,10 @@ func getMap(line string) map[string]string {
 	// create and fill the map
 	m := make(map[string]string)
 	for _, item := range items {
-		x := strings.Split(item, "=")
+		x := strings.SplitN(item, "=", 2)
+		if len(x) != 2 {
+			continue
+		}
 		m[x[0]] = x[1]
 	}
 	return m
@@ -561,6 +810,12 @@ func (c *Client) parseNodeInfo(line string) (*NodeInfo, error) {
 		CfgStatSysThreads:     kv["cfg.stat.sys.threads"],
 		SumStatNetInratemib:   kv["sum.stat.net.inratemib"],
 		SumStatNetOutratemib:  kv["sum.stat.net.outratemib"],
+		HeartbeatDelta:        kv["heartbeatdelta"],
+		CfgStatus:             kv["cfg.status"],
+		CfgStatGeotag:         kv["cfg.stat.geotag"],
+		CfgTxgw:               kv["cfg.txgw"],
+		CfgGwNtx:              kv["cfg.gw.ntx"],
+		CfgGwRate:             kv["cfg.gw.rate"],
 	}
 	return fst, nil
 }
@@ -794,92 +1049,307 @@ func (c *Client) parseVSsInfo(mgmVersion string, nodeLSResponse *NodeLSResponse)
 	return vsinfos, nil
 }
 
-// Gathers information of the namespace
+// Gathers information of the namespace.
+// The statistics are reported one per line and merged into a single NSInfo.
 func (c *Client) parseNSsInfo(raw string) ([]*NSInfo, []*NSActivityInfo, error) {
-	var kv map[string]string
-	var nsinfo *NSInfo
-	var nsactinfo *NSActivityInfo
 	nsinfos := []*NSInfo{}
 	nsactinfos := []*NSActivityInfo{}
+	stats := make(map[string]string)
 	rawLines := strings.Split(raw, "\n")
 	for _, rl := range rawLines {
 		if rl == "" {
 			continue
 		}
-		kv = getMap(rl)
-		// Only expose global data, without breakdown of users
-		if kv["uid"] == "all" && kv["gid"] == "all" {
-			// Separate activity info from namespace statistics info
-			if _, ok := kv["cmd"]; ok {
-				if kv["5s"] == "0.00" && kv["60s"] == "0.00" && kv["300s"] == "0.00" && kv["3600s"] == "0.00" {
-				} else {
-					nsactinfo = &NSActivityInfo{
-						kv["uid"],
-						kv["gid"],
-						kv["cmd"],
-						kv["total"],
-						kv["5s"],
-						kv["60s"],
-						kv["300s"],
-						kv["3600s"],
-						kv["exec"],
-						kv["execsig"],
-						kv["exec99"],
-						kv["execmax"],
-					}
-				}
-			} else {
-				if len(kv) <= 3 {
-					for k := range kv {
-						if k != "uid" && k != "gid" {
-							if _, err := strconv.ParseFloat(kv[k], 64); err != nil {
-								fmt.Sprintf("Value of '%s': '%s' is not floatable", k, kv[k])
-							}
-							nsinfo = &NSInfo{
-								kv["ns.boot.file.time"],
-								kv["ns.boot.status"],
-								kv["ns.boot.time"],
-								kv["ns.cache.containers.maxsize"],
-								kv["ns.cache.containers.occupancy"],
-								kv["ns.cache.files.maxsize"],
-								kv["ns.cache.files.occupancy"],
-								kv["ns.fds.all"],
-								kv["ns.fusex.activeclients"],
-								kv["ns.fusex.caps"],
-								kv["ns.fusex.clients"],
-								kv["ns.fusex.lockedclients"],
-								kv["ns.latency.dirs"],
-								kv["ns.latency.files"],
-								kv["ns.latency.pending.updates"],
-								kv["ns.latencypeak.eosviewmutex.1min"],
-								kv["ns.latencypeak.eosviewmutex.2min"],
-								kv["ns.latencypeak.eosviewmutex.5min"],
-								kv["ns.latencypeak.eosviewmutex.last"],
-								kv["ns.memory.growth"],
-								kv["ns.memory.resident"],
-								kv["ns.memory.share"],
-								kv["ns.memory.virtual"],
-								kv["ns.stat.threads"],
-								kv["ns.total.directories"],
-								kv["ns.total.directories.changelog.avg_entry_size"],
-								kv["ns.total.directories.changelog.size"],
-								kv["ns.total.files"],
-								kv["ns.total.files.changelog.avg_entry_size"],
-								kv["ns.total.files.changelog.size"],
-								kv["ns.uptime"],
-							}
-						}
+		kv := getMap(rl)
+		// Separate activity info, global or per uid and gid, from namespace statistics info
+		if _, ok := kv["cmd"]; ok {
+			if kv["5s"] == "0.00" && kv["60s"] == "0.00" && kv["300s"] == "0.00" && kv["3600s"] == "0.00" {
+				continue
+			}
+			nsactinfos = append(nsactinfos, &NSActivityInfo{
+				kv["uid"],
+				kv["gid"],
+				kv["cmd"],
+				kv["total"],
+				kv["5s"],
+				kv["60s"],
+				kv["300s"],
+				kv["3600s"],
+				kv["exec"],
+				kv["execsig"],
+				kv["exec99"],
+				kv["execmax"],
+			})
+			continue
+		}
+		// Only expose global statistics, without breakdown of users
+		if kv["uid"] != "all" || kv["gid"] != "all" {
+			continue
+		}
+		if len(kv) <= 3 {
+			for k, v := range kv {
+				if k != "uid" && k != "gid" {
+					if _, err := strconv.ParseFloat(v, 64); err != nil && c.opt.EnableLogging {
+						c.opt.Logger.Warn(fmt.Sprintf("Value of '%s': '%s' is not floatable", k, v))
 					}
+					stats[k] = v
 				}
 			}
+		}
+	}
+	if len(stats) != 0 {
+		nsinfos = append(nsinfos, &NSInfo{
+			stats["ns.boot.file.time"],
+			stats["ns.boot.status"],
+			stats["ns.boot.time"],
+			stats["ns.cache.containers.maxsize"],
+			stats["ns.cache.containers.occupancy"],
+			stats["ns.cache.files.maxsize"],
+			stats["ns.cache.files.occupancy"],
+			stats["ns.fds.all"],
+			stats["ns.fusex.activeclients"],
+			stats["ns.fusex.caps"],
+			stats["ns.fusex.clients"],
+			stats["ns.fusex.lockedclients"],
+			stats["ns.latency.dirs"],
+			stats["ns.latency.files"],
+			stats["ns.latency.pending.updates"],
+			stats["ns.latencypeak.eosviewmutex.1min"],
+			stats["ns.latencypeak.eosviewmutex.2min"],
+			stats["ns.latencypeak.eosviewmutex.5min"],
+			stats["ns.latencypeak.eosviewmutex.last"],
+			stats["ns.memory.growth"],
+			stats["ns.memory.resident"],
+			stats["ns.memory.share"],
+			stats["ns.memory.virtual"],
+			stats["ns.stat.threads"],
+			stats["ns.total.directories"],
+			stats["ns.total.directories.changelog.avg_entry_size"],
+			stats["ns.total.directories.changelog.size"],
+			stats["ns.total.files"],
+			stats["ns.total.files.changelog.avg_entry_size"],
+			stats["ns.total.files.changelog.size"],
+			stats["ns.uptime"],
+		})
+	}
+	return nsinfos, nsactinfos, nil
+}
 
+// Gathers the quotas of all quota nodes
+func (c *Client) parseQuotasInfo(raw string) ([]*QuotaInfo, error) {
+	quotainfos := []*QuotaInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.HasPrefix(rl, "quota=node") {
+			continue
 		}
-		if nsinfo != nil {
-			nsinfos = append(nsinfos, nsinfo)
+		quota, err := c.parseQuotaInfo(rl)
+
+		if err != nil {
+			return nil, err
 		}
-		if nsactinfo != nil {
-			nsactinfos = append(nsactinfos, nsactinfo)
+		quotainfos = append(quotainfos, quota)
+	}
+	return quotainfos, nil
+}
+
+// Gathers the quota of one user, group or project on a quota node
+func (c *Client) parseQuotaInfo(line string) (*QuotaInfo, error) {
+	kv := getMap(line)
+	if kv["space"] == "" {
+		return nil, fmt.Errorf("quota line without quota node: %q", line)
+	}
+	quota := &QuotaInfo{
+		kv["space"],
+		kv["uid"],
+		kv["gid"],
+		kv["usedbytes"],
+		kv["usedlogicalbytes"],
+		kv["usedfiles"],
+		kv["maxbytes"],
+		kv["maxlogicalbytes"],
+		kv["maxfiles"],
+		kv["percentageusedbytes"],
+		kv["statusbytes"],
+		kv["statusfiles"],
+	}
+	return quota, nil
+}
+
+// Gathers the error counts of an fsck report
+func (c *Client) parseFsckReportsInfo(raw string) ([]*FsckReportInfo, error) {
+	reportinfos := []*FsckReportInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.Contains(rl, "tag=") {
+			continue
 		}
+		kv := getMap(rl)
+		reportinfos = append(reportinfos, &FsckReportInfo{
+			kv["timestamp"],
+			kv["fsid"],
+			strings.Trim(kv["tag"], `"`),
+			kv["count"],
+		})
 	}
-	return nsinfos, nsactinfos, nil
+	return reportinfos, nil
+}
+
+// Gathers the status of the fsck threads, from lines like
+// "Info: collection thread status -> enabled"
+func (c *Client) parseFsckStatInfo(raw string) (*FsckStatInfo, error) {
+	stat := &FsckStatInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		x := strings.SplitN(rl, "->", 2)
+		if len(x) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(x[1])
+		switch {
+		case strings.Contains(x[0], "collection thread status"):
+			stat.CollectionThread = value
+		case strings.Contains(x[0], "repair thread status"):
+			stat.RepairThread = value
+		}
+	}
+	if stat.CollectionThread == "" && stat.RepairThread == "" {
+		return nil, errors.New("fsck thread status not found")
+	}
+	return stat, nil
+}
+
+// Gathers the information of the recycle bin, the percentages are returned without the % sign
+func (c *Client) parseRecycleInfo(raw string) (*RecycleInfo, error) {
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.HasPrefix(rl, "recycle-bin=") {
+			continue
+		}
+		kv := getMap(rl)
+		recycle := &RecycleInfo{
+			kv["recycle-bin"],
+			kv["usedbytes"],
+			kv["maxbytes"],
+			strings.TrimSuffix(kv["volumeusage"], "%"),
+			strings.TrimSuffix(kv["inodeusage"], "%"),
+			kv["lifetime"],
+			kv["ratio"],
+		}
+		return recycle, nil
+	}
+	return nil, errors.New("recycle bin information not found")
+}
+
+// Gathers the io statistics, one per measurement line.
+// The uid, gid, application and domain are empty when the line is not broken down by them.
+func (c *Client) parseIOStatsInfo(raw string) ([]*IOStatInfo, error) {
+	stats := []*IOStatInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.Contains(rl, "measurement=") {
+			continue
+		}
+		kv := getMap(rl)
+		app := kv["application"]
+		if app == "" {
+			app = kv["app"]
+		}
+		stats = append(stats, &IOStatInfo{
+			kv["uid"],
+			kv["gid"],
+			app,
+			kv["domain"],
+			kv["measurement"],
+			kv["total"],
+		})
+	}
+	return stats, nil
+}
+
+// Gathers the information of the fusex clients, one per line.
+// Delta is the time since the last heartbeat of the client, in seconds.
+func (c *Client) parseFusexsInfo(raw string) ([]*FusexInfo, error) {
+	fusexinfos := []*FusexInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.Contains(rl, "uuid=") {
+			continue
+		}
+		fusexinfo, err := c.parseFusexInfo(rl)
+		if err != nil {
+			return nil, err
+		}
+		fusexinfos = append(fusexinfos, fusexinfo)
+	}
+	return fusexinfos, nil
+}
+
+func (c *Client) parseFusexInfo(line string) (*FusexInfo, error) {
+	kv := getMap(line)
+	delta := kv["delta"]
+	if delta == "" {
+		delta = kv["dt"]
+	}
+	fusex := &FusexInfo{
+		kv["host"],
+		kv["uuid"],
+		kv["version"],
+		kv["state"],
+		kv["mount"],
+		kv["caps"],
+		delta,
+	}
+	return fusex, nil
+}
+
+// Gathers the client sessions, one per line. The summaries per uid and auth are skipped.
+func (c *Client) parseWhosInfo(raw string) ([]*WhoInfo, error) {
+	whoinfos := []*WhoInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		if !strings.Contains(rl, "client=") {
+			continue
+		}
+		kv := getMap(rl)
+		protocol := kv["prot"]
+		if protocol == "" {
+			protocol = kv["protocol"]
+		}
+		whoinfos = append(whoinfos, &WhoInfo{
+			kv["client"],
+			kv["uid"],
+			kv["auth"],
+			protocol,
+			strings.Trim(kv["app"], `"`),
+		})
+	}
+	return whoinfos, nil
+}
+
+// Gathers the access rules, from lines like "user.banned=1000" or "stall.r:*=60"
+func (c *Client) parseAccessInfo(raw string) ([]*AccessInfo, error) {
+	accessinfos := []*AccessInfo{}
+	rawLines := strings.Split(raw, "\n")
+	for _, rl := range rawLines {
+		for k, v := range getMap(rl) {
+			accessinfos = append(accessinfos, &AccessInfo{k, v})
+		}
+	}
+	return accessinfos, nil
+}
+
+// Gathers the size and checksum of a file
+func (c *Client) parseFileInfo(raw string) (*FileInfo, error) {
+	kv := getMap(strings.TrimSpace(raw))
+	if kv["size"] == "" {
+		return nil, errors.New("file size not found")
+	}
+	file := &FileInfo{
+		kv["file"],
+		kv["size"],
+		kv["xstype"],
+		kv["xs"],
+	}
+	return file, nil
 }
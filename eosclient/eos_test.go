@@ -0,0 +1,493 @@
+package eosclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestClient returns a client running the commands against the canned responses.
+func newTestClient(t *testing.T, responses map[string]FakeResponse) (*Client, *FakeRunner) {
+	t.Helper()
+	runner := NewFakeRunner(responses)
+	c, err := New(&Options{URL: "root://eos-test.example.org", RoleUser: "0", RoleGroup: "0", Runner: runner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, runner
+}
+
+func TestFakeRunner(t *testing.T) {
+	runner := NewFakeRunner(map[string]FakeResponse{
+		"ls -m":      {Stdout: "any"},
+		"node ls -m": {Stdout: "node"},
+		"version":    {Stderr: "unreachable", Status: 3},
+	})
+
+	tests := []struct {
+		args   []string
+		stdout string
+		status int
+		err    bool
+	}{
+		{[]string{"node", "ls", "-m"}, "node", 0, false},
+		{[]string{"-r", "0", "0", "node", "ls", "-m"}, "node", 0, false},
+		{[]string{"-r", "0", "0", "space", "ls", "-m"}, "any", 0, false},
+		{[]string{"version"}, "", 3, false},
+		{[]string{"nodels", "-m"}, "", 1, true},
+		{[]string{"node", "ls"}, "", 1, true},
+	}
+	for _, tt := range tests {
+		stdout, _, status, err := runner.Run(context.Background(), nil, "eos", tt.args...)
+		if stdout != tt.stdout || status != tt.status || (err != nil) != tt.err {
+			t.Errorf("Run(%q) = %q, %d, %v, want %q, %d, error %t", tt.args, stdout, status, err, tt.stdout, tt.status, tt.err)
+		}
+	}
+
+	if calls := runner.Calls(); len(calls) != len(tests) || calls[1][0] != "eos" || calls[1][1] != "-r" {
+		t.Errorf("Calls() = %q", calls)
+	}
+}
+
+func TestEosArguments(t *testing.T) {
+	c, runner := newTestClient(t, map[string]FakeResponse{"node ls -m": {}})
+	if _, err := c.ListNode(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"/usr/bin/eos", "-r", "0", "0", "node", "ls", "-m"}}
+	if calls := runner.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls() = %q, want %q", calls, want)
+	}
+}
+
+func TestExecuteError(t *testing.T) {
+	c, _ := newTestClient(t, map[string]FakeResponse{
+		"node ls -m":  {Stderr: "error: no such node\n  (errc=2)\n", Status: 2},
+		"space ls -m": {Err: errors.New("exec: not found")},
+	})
+
+	_, err := c.ListNode(context.Background())
+	if err == nil {
+		t.Fatal("ListNode() succeeded, want an error")
+	}
+	if want := "/usr/bin/eos -r 0 0 node ls -m: exit status 2: error: no such node (errc=2)"; err.Error() != want {
+		t.Errorf("ListNode() error = %q, want %q", err, want)
+	}
+
+	if _, err := c.ListSpace(context.Background()); err == nil || !strings.HasSuffix(err.Error(), "space ls -m: exec: not found") {
+		t.Errorf("ListSpace() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ListGroup(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListGroup() with a cancelled context, error = %v", err)
+	}
+}
+
+func TestTrimStderr(t *testing.T) {
+	long := strings.Repeat("x", maxStderr+10)
+	tests := []struct {
+		stderr string
+		want   string
+	}{
+		{"", ""},
+		{"  error:\tfailed\n\n", "error: failed"},
+		{long, long[:maxStderr] + "..."},
+	}
+	for _, tt := range tests {
+		if got := trimStderr(tt.stderr); got != tt.want {
+			t.Errorf("trimStderr(%q) = %q, want %q", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestGetRole(t *testing.T) {
+	tests := []struct {
+		user, group string
+		uid, gid    string
+		err         bool
+	}{
+		{"0", "", "0", "0", false},
+		{"root", "", "0", "0", false},
+		{"1234", "5678", "1234", "5678", false},
+		{"0", "99", "0", "99", false},
+		{"4294967000", "", "", "", true},
+		{"no-such-user-eos-exporter", "", "", "", true},
+		{"0", "no-such-group-eos-exporter", "", "", true},
+	}
+	for _, tt := range tests {
+		uid, gid, err := getRole(tt.user, tt.group)
+		if uid != tt.uid || gid != tt.gid || (err != nil) != tt.err {
+			t.Errorf("getRole(%q, %q) = %q, %q, %v, want %q, %q, error %t", tt.user, tt.group, uid, gid, err, tt.uid, tt.gid, tt.err)
+		}
+	}
+}
+
+func TestGetMap(t *testing.T) {
+	tests := []struct {
+		line string
+		want map[string]string
+	}{
+		{"", map[string]string{}},
+		{"a=1 b=2", map[string]string{"a": "1", "b": "2"}},
+		{`a= b="x y" c=d=e novalue`, map[string]string{"a": "", "b": `"x y"`, "c": "d=e"}},
+	}
+	for _, tt := range tests {
+		if got := getMap(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("getMap(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseNodesInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "type=nodesview hostport=fst1.example.org:1095 status=online nofs=2 sum.stat.statfs.freebytes=100 sum.stat.statfs.usedbytes=50 sum.stat.statfs.capacity=150 sum.stat.ropen=1 sum.stat.wopen=2 cfg.stat.sys.threads=100\n" +
+		"\n" +
+		"type=nodesview hostport=fst2.example.org:1095 status=offline nofs=1\n"
+
+	nodes, err := c.parseNodesInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*NodeInfo{
+		{Hostport: "fst1.example.org:1095", Status: "online", Nofs: "2", SumStatStatfsFree: "100", SumStatStatfsUsed: "50", SumStatStatfsTotal: "150", SumStatRopen: "1", SumStatWopen: "2", CfgStatSysThreads: "100"},
+		{Hostport: "fst2.example.org:1095", Status: "offline", Nofs: "1"},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("parseNodesInfo() = %+v, want %+v", nodes, want)
+	}
+}
+
+func TestParseNodeInfoGateway(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	node, err := c.parseNodeInfo("type=nodesview hostport=fst1.example.org:1095 status=online heartbeatdelta=2 cfg.status=on cfg.stat.geotag=cern::0513 cfg.txgw=on cfg.gw.ntx=10 cfg.gw.rate=120")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &NodeInfo{Hostport: "fst1.example.org:1095", Status: "online", HeartbeatDelta: "2", CfgStatus: "on", CfgStatGeotag: "cern::0513", CfgTxgw: "on", CfgGwNtx: "10", CfgGwRate: "120"}
+	if !reflect.DeepEqual(node, want) {
+		t.Errorf("parseNodeInfo() = %+v, want %+v", node, want)
+	}
+}
+
+func TestParseSpacesInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "type=spaceview name=default cfg.groupsize=24 cfg.groupmod=24 nofs=3 avg.stat.disk.load=0.1 sum.stat.statfs.usedbytes=55 sum.stat.statfs.capacity=165 cfg.quota=on cfg.balancer=off sum.stat.statfs.capacity?configstatus@rw=110 sum.<n>?configstatus@rw=2\n" +
+		"type=spaceview name=spare nofs=0\n"
+
+	spaces, err := c.parseSpacesInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spaces) != 2 {
+		t.Fatalf("parseSpacesInfo() returned %d spaces, want 2", len(spaces))
+	}
+	s := spaces[0]
+	got := []string{s.Type, s.Name, s.CfgGroupSize, s.CfgGroupMod, s.Nofs, s.AvgStatDiskLoad, s.SumStatStatfsUsedbytes, s.SumStatStatfsCapacity, s.CfgQuota, s.CfgBalancer, s.SumStatStatfsCapacityConfigstatusRw, s.SumNofsConfigstatusRw}
+	want := []string{"spaceview", "default", "24", "24", "3", "0.1", "55", "165", "on", "off", "110", "2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSpacesInfo() first space = %q, want %q", got, want)
+	}
+	if spaces[1].Name != "spare" || spaces[1].CfgGroupSize != "" {
+		t.Errorf("parseSpacesInfo() second space = %+v", spaces[1])
+	}
+}
+
+func TestParseGroupsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "type=groupview name=default.0 cfg.status=on nofs=2 sum.stat.statfs.usedbytes=50 cfg.stat.balancing=idle\n" +
+		"type=groupview name=default.1 cfg.status=off nofs=1 sum.stat.statfs.usedbytes=5 cfg.stat.balancing=balancing dev.stat.statfs.filled=1.5\n"
+
+	groups, err := c.parseGroupsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*GroupInfo{
+		{Name: "default.0", CfgStatus: "on", Nofs: "2", SumStatStatfsUsedbytes: "50", CfgStatBalancing: "idle"},
+		{Name: "default.1", CfgStatus: "off", Nofs: "1", SumStatStatfsUsedbytes: "5", CfgStatBalancing: "balancing", DevStatStatfsFilled: "1.5"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("parseGroupsInfo() = %+v, want %+v", groups, want)
+	}
+}
+
+func TestParseFSsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "type=fsview host=fst1.example.org port=1095 id=1 uuid=aaaa path=/data01 schedgroup=default.0 stat.boot=booted configstatus=rw stat.errc=0 stat.errmsg= stat.active=online stat.geotag=site::rack1 drainstatus=nodrain\n" +
+		"type=fsview host=fst1.example.org port=1095 id=2 uuid=bbbb path=/data02 schedgroup=default.0 stat.boot=opserror configstatus=ro stat.errc=5 stat.errmsg=\"Input/output error\" stat.active=online\n"
+
+	fss, err := c.parseFSsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*FSInfo{
+		{Host: "fst1.example.org", Port: "1095", Id: "1", Uuid: "aaaa", Path: "/data01", Schedgroup: "default.0", StatBoot: "booted", Configstatus: "rw", StatErrc: "0", StatActive: "online", StatGeotag: "site::rack1", Drainstatus: "nodrain"},
+		{Host: "fst1.example.org", Port: "1095", Id: "2", Uuid: "bbbb", Path: "/data02", Schedgroup: "default.0", StatBoot: "opserror", Configstatus: "ro", StatErrc: "5", StatErrmsg: `"Input/output error"`, StatActive: "online"},
+	}
+	if !reflect.DeepEqual(fss, want) {
+		t.Errorf("parseFSsInfo() = %+v, want %+v", fss, want)
+	}
+}
+
+func TestListVS(t *testing.T) {
+	c, _ := newTestClient(t, map[string]FakeResponse{
+		"version": {Stdout: "EOS_INSTANCE=eostest\nEOS_SERVER_VERSION=5.1.0 EOS_SERVER_RELEASE=1\n"},
+		"--json node ls": {Stdout: `{"errormsg":"","result":[{"hostport":"fst1.example.org:1095","cfg":{"stat":{"geotag":"site::rack1","sys":{"eos":{"start":"Mon","version":"5.1.1"},"kernel":"5.14","rss":1,"sockets":2,"threads":3,"uptime":"10:00%20up%2012%20days,","vsize":4,"xrootd":{"version":"5.5"}}}}},` +
+			`{"hostport":"fst2.example.org:1095","cfg":{"stat":{"sys":{"uptime":"10:00"}}}}]}`},
+	})
+
+	vss, err := c.ListVS(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*VSInfo{
+		{EOSmgm: "5.1.0", Hostname: "fst1.example.org", Port: "1095", Geotag: "site::rack1", Vsize: "4", Rss: "1", Threads: "3", Sockets: "2", EOSfst: "5.1.1", Xrootdfst: "5.5", KernelV: "5.14", Start: "Mon", Uptime: "12"},
+		{EOSmgm: "5.1.0", Hostname: "fst2.example.org", Port: "1095", Vsize: "0", Rss: "0", Threads: "0", Sockets: "0", Uptime: "0"},
+	}
+	if !reflect.DeepEqual(vss, want) {
+		t.Errorf("ListVS() = %+v, want %+v", vss, want)
+	}
+}
+
+func TestParseNSsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "uid=all gid=all ns.total.files=100\n" +
+		"uid=all gid=all ns.total.directories=10\n" +
+		"uid=all gid=all ns.uptime=1000\n" +
+		"uid=all gid=all ns.boot.status=booted\n" +
+		"uid=all gid=all cmd=Stat total=500 5s=1.00 60s=2.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+		"uid=all gid=all cmd=Open total=50 5s=0.00 60s=0.00 300s=0.00 3600s=0.00 exec=0 execsig=0 exec99=0 execmax=0\n"
+
+	nss, acts, err := c.parseNSsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nss) != 1 {
+		t.Fatalf("parseNSsInfo() returned %d statistics, want them merged into 1", len(nss))
+	}
+	ns := nss[0]
+	if got, want := []string{ns.Total_files, ns.Total_directories, ns.Uptime, ns.Boot_status}, []string{"100", "10", "1000", "booted"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNSsInfo() statistics = %q, want %q", got, want)
+	}
+
+	// the idle Open is left out
+	want := []*NSActivityInfo{
+		{"all", "all", "Stat", "500", "1.00", "2.00", "3.00", "4.00", "0.5", "0.1", "2.0", "10.0"},
+	}
+	if !reflect.DeepEqual(acts, want) {
+		t.Errorf("parseNSsInfo() activity = %+v, want %+v", acts, want)
+	}
+
+	if nss, acts, err := c.parseNSsInfo(""); err != nil || len(nss) != 0 || len(acts) != 0 {
+		t.Errorf("parseNSsInfo(\"\") = %v, %v, %v, want nothing", nss, acts, err)
+	}
+}
+
+func TestParseNSsInfoByID(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "uid=all gid=all ns.total.files=100\n" +
+		"uid=1000 gid=all ns.total.files=60\n" +
+		"uid=1000 gid=all cmd=Stat total=300 5s=1.00 60s=5.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n" +
+		"uid=all gid=500 cmd=Stat total=500 5s=1.00 60s=9.00 300s=3.00 3600s=4.00 exec=0.5 execsig=0.1 exec99=2.0 execmax=10.0\n"
+
+	nss, acts, err := c.parseNSsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the statistics of a single uid do not override the ones of the instance
+	if len(nss) != 1 || nss[0].Total_files != "100" {
+		t.Errorf("parseNSsInfo() statistics = %+v, want the files of uid=all gid=all", nss)
+	}
+	want := []*NSActivityInfo{
+		{"1000", "all", "Stat", "300", "1.00", "5.00", "3.00", "4.00", "0.5", "0.1", "2.0", "10.0"},
+		{"all", "500", "Stat", "500", "1.00", "9.00", "3.00", "4.00", "0.5", "0.1", "2.0", "10.0"},
+	}
+	if !reflect.DeepEqual(acts, want) {
+		t.Errorf("parseNSsInfo() activity = %+v, want %+v", acts, want)
+	}
+}
+
+func TestParseQuotasInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "quota=node uid=alice space=/eos/user/ usedbytes=200 usedlogicalbytes=100 usedfiles=3 maxbytes=2000 maxlogicalbytes=1000 maxfiles=100 percentageusedbytes=10.00 statusbytes=ok statusfiles=ok\n" +
+		"quota=node gid=99 space=/eos/project/atlas/ usedbytes=2 usedlogicalbytes=1 usedfiles=1 maxbytes=4 maxlogicalbytes=2 maxfiles=10 percentageusedbytes=50.00 statusbytes=ok statusfiles=ok\n" +
+		"# quota node listing header\n" +
+		"\n"
+
+	quotas, err := c.parseQuotasInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*QuotaInfo{
+		{"/eos/user/", "alice", "", "200", "100", "3", "2000", "1000", "100", "10.00", "ok", "ok"},
+		{"/eos/project/atlas/", "", "99", "2", "1", "1", "4", "2", "10", "50.00", "ok", "ok"},
+	}
+	if !reflect.DeepEqual(quotas, want) {
+		t.Errorf("parseQuotasInfo() = %+v, want %+v", quotas, want)
+	}
+
+	if _, err := c.parseQuotasInfo("quota=node uid=alice usedbytes=200\n"); err == nil {
+		t.Error("parseQuotasInfo() succeeded on a quota without quota node")
+	}
+}
+
+func TestParseFsckReportsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "timestamp=1614596400 fsid=1 tag=\"d_mem_sz_diff\" count=10\n" +
+		"timestamp=1614596400 tag=rep_missing_n count=3\n" +
+		"no report\n"
+
+	reports, err := c.parseFsckReportsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*FsckReportInfo{
+		{"1614596400", "1", "d_mem_sz_diff", "10"},
+		{"1614596400", "", "rep_missing_n", "3"},
+	}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("parseFsckReportsInfo() = %+v, want %+v", reports, want)
+	}
+}
+
+func TestParseFsckStatInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	tests := []struct {
+		raw  string
+		want *FsckStatInfo
+	}{
+		{"Info: collection thread status -> enabled\nInfo: repair thread status     -> disabled\nInfo: repair category          -> all\n", &FsckStatInfo{"enabled", "disabled"}},
+		{"Info: repair thread status -> enabled\n", &FsckStatInfo{"", "enabled"}},
+		{"Info: repair category -> all\n", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		stat, err := c.parseFsckStatInfo(tt.raw)
+		if !reflect.DeepEqual(stat, tt.want) || (err != nil) != (tt.want == nil) {
+			t.Errorf("parseFsckStatInfo(%q) = %+v, %v, want %+v", tt.raw, stat, err, tt.want)
+		}
+	}
+}
+
+func TestParseRecycleInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "# recycle bin\n" +
+		"recycle-bin=/eos/dev/proc/recycle/ usedbytes=100 maxbytes=1000 volumeusage=10.00% inodeusage=1.50% lifetime=604800 ratio=0.800000\n"
+
+	recycle, err := c.parseRecycleInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RecycleInfo{"/eos/dev/proc/recycle/", "100", "1000", "10.00", "1.50", "604800", "0.800000"}
+	if !reflect.DeepEqual(recycle, want) {
+		t.Errorf("parseRecycleInfo() = %+v, want %+v", recycle, want)
+	}
+
+	if _, err := c.parseRecycleInfo("error: no recycle bin\n"); err == nil {
+		t.Error("parseRecycleInfo() succeeded without a recycle-bin line")
+	}
+}
+
+func TestParseIOStatsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "uid=all gid=all measurement=bytes_read total=1000 60s=10 300s=50 3600s=500 86400s=1000\n" +
+		"uid=1000 measurement=bytes_written total=400 60s=4 300s=20 3600s=200 86400s=400\n" +
+		"measurement=bytes_read application=eoscp total=800 60s=8 300s=40 3600s=400 86400s=800\n" +
+		"measurement=bytes_read app=xrdcp total=50 60s=0 300s=0 3600s=0 86400s=50\n" +
+		"measurement=bytes_read domain=.cern.ch total=900 60s=9 300s=45 3600s=450 86400s=900\n" +
+		"no io statistics\n"
+
+	stats, err := c.parseIOStatsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*IOStatInfo{
+		{"all", "all", "", "", "bytes_read", "1000"},
+		{"1000", "", "", "", "bytes_written", "400"},
+		{"", "", "eoscp", "", "bytes_read", "800"},
+		{"", "", "xrdcp", "", "bytes_read", "50"},
+		{"", "", "", ".cern.ch", "bytes_read", "900"},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("parseIOStatsInfo() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestParseFusexsInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "client=eosxd host=lxplus1.cern.ch version=4.8.40 state=online tstart=1614596400 tsnow=1614600000 delta=0.25 uuid=a1 pid=100 caps=12 fds=3 type=static mount=/eos\n" +
+		"client=eosxd host=lxplus2.cern.ch version=4.8.51 state=evicted dt=3600 uuid=c3 caps=0 mount=/eos\n" +
+		"no clients\n"
+
+	fusexs, err := c.parseFusexsInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*FusexInfo{
+		{"lxplus1.cern.ch", "a1", "4.8.40", "online", "/eos", "12", "0.25"},
+		{"lxplus2.cern.ch", "c3", "4.8.51", "evicted", "/eos", "0", "3600"},
+	}
+	if !reflect.DeepEqual(fusexs, want) {
+		t.Errorf("parseFusexsInfo() = %+v, want %+v", fusexs, want)
+	}
+}
+
+func TestParseWhosInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "auth=krb5 nsessions=2\n" +
+		"client=alice.1:2@lxplus1 uid=alice auth=krb5 idle=3 gateway=\"\" app=\"\"\n" +
+		"client=bob.1:2@lxplus2 uid=bob auth=https idle=3 gateway=\"gw\" app=\"http\" prot=https\n" +
+		"client=carl.1:2@lxplus2 uid=carl auth=sss protocol=xroot app=\"eosxd\"\n"
+
+	whos, err := c.parseWhosInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*WhoInfo{
+		{"alice.1:2@lxplus1", "alice", "krb5", "", ""},
+		{"bob.1:2@lxplus2", "bob", "https", "https", "http"},
+		{"carl.1:2@lxplus2", "carl", "sss", "xroot", "eosxd"},
+	}
+	if !reflect.DeepEqual(whos, want) {
+		t.Errorf("parseWhosInfo() = %+v, want %+v", whos, want)
+	}
+}
+
+func TestParseAccessInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	raw := "user.banned=1000\nhost.banned=bad.cern.ch\n\nstall.rate:user:*:OpenRead=100\nredirect.w:*=eos-backup.cern.ch:1094\n"
+
+	access, err := c.parseAccessInfo(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*AccessInfo{
+		{"user.banned", "1000"},
+		{"host.banned", "bad.cern.ch"},
+		{"stall.rate:user:*:OpenRead", "100"},
+		{"redirect.w:*", "eos-backup.cern.ch:1094"},
+	}
+	if !reflect.DeepEqual(access, want) {
+		t.Errorf("parseAccessInfo() = %+v, want %+v", access, want)
+	}
+}
+
+func TestParseFileInfo(t *testing.T) {
+	c, _ := newTestClient(t, nil)
+	info, err := c.parseFileInfo("keylength.file=33 file=/eos/test/probe/eos_exporter.x size=4096 mtime=1614596400.0 xstype=adler xs=0a0b0c0d\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &FileInfo{"/eos/test/probe/eos_exporter.x", "4096", "adler", "0a0b0c0d"}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("parseFileInfo() = %+v, want %+v", info, want)
+	}
+
+	if _, err := c.parseFileInfo("error: no such file or directory\n"); err == nil {
+		t.Error("parseFileInfo() succeeded without a size")
+	}
+}
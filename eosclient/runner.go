@@ -0,0 +1,97 @@
+package eosclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Runner executes the commands issued by the Client.
+type Runner interface {
+	// Run executes the binary name with the given arguments and environment and
+	// returns its stdout, stderr and exit status.
+	Run(ctx context.Context, env []string, name string, args ...string) (string, string, int, error)
+}
+
+// ExecRunner runs the commands as local processes.
+type ExecRunner struct{}
+
+// Run executes the command and waits for it to finish.
+func (ExecRunner) Run(ctx context.Context, env []string, name string, args ...string) (string, string, int, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env
+
+	outBuf := &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	cmd.Stdout = outBuf
+	cmd.Stderr = errBuf
+	err := cmd.Run()
+
+	status := 0
+	if exiterr, ok := err.(*exec.ExitError); ok {
+		// The program has exited with an exit code != 0
+		status = exiterr.ExitCode()
+	}
+	return outBuf.String(), errBuf.String(), status, err
+}
+
+// FakeResponse is the canned result of a command served by FakeRunner.
+type FakeResponse struct {
+	Stdout string
+	Stderr string
+	Status int
+	Err    error
+}
+
+// FakeRunner is an in-memory Runner meant for tests.
+// Responses are keyed by the command arguments joined with a single space,
+// without the binary name. A command is served by the longest key it ends
+// with, so "node ls -m" matches regardless of the global options before it.
+type FakeRunner struct {
+	Responses map[string]FakeResponse
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+// NewFakeRunner returns a FakeRunner serving the given responses.
+func NewFakeRunner(responses map[string]FakeResponse) *FakeRunner {
+	return &FakeRunner{Responses: responses}
+}
+
+// Run records the call and returns the matching canned response.
+func (f *FakeRunner) Run(ctx context.Context, env []string, name string, args ...string) (string, string, int, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, append([]string{name}, args...))
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", "", -1, err
+	}
+
+	cmdline := strings.Join(args, " ")
+	var (
+		match FakeResponse
+		found bool
+		best  = -1
+	)
+	for k, r := range f.Responses {
+		if (cmdline == k || strings.HasSuffix(cmdline, " "+k)) && len(k) > best {
+			match, found, best = r, true, len(k)
+		}
+	}
+	if !found {
+		return "", "", 1, fmt.Errorf("fake runner: no response for %q", cmdline)
+	}
+	return match.Stdout, match.Stderr, match.Status, match.Err
+}
+
+// Calls returns the commands run so far, binary name included.
+func (f *FakeRunner) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.calls...)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+// probeHandler serves the metrics of the instance given in the target query parameter,
+// which must be listed in the configuration in use, either by name or by MGM URL.
+// The exporter of the instance is built on each request, so probes are never served from a snapshot,
+// and the collectors running in the background, like xrdcopy, are left out.
+func probeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := currentState()
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+		name, inst, ok := s.cfg.instance(target)
+		if !ok {
+			http.Error(w, "unknown target "+target, http.StatusBadRequest)
+			return
+		}
+
+		opt := &eosclient.Options{
+			URL:            inst.MGMURL,
+			EosBinary:      s.opts.EOSBinary,
+			XrdcopyBinary:  s.opts.XrdcopyBinary,
+			RoleUser:       s.opts.EOSRoleUser,
+			RoleGroup:      s.opts.EOSRoleGroup,
+			Keytab:         inst.Keytab,
+			CommandTimeout: s.opts.EOSCommandTimeout,
+		}
+		overrideRole(&opt.RoleUser, &opt.RoleGroup, inst.RoleUser, inst.RoleGroup)
+		exporter, err := NewEOSExporter(name, opt, s.opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// the exporter only lives for this request, the collectors running on their own schedule would never report
+		exporter.withoutBackground()
+		serveMetrics(w, r, s, exporter)
+	})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+	"gitlab.cern.ch/rvalverd/eos_exporter/eosclient"
+)
+
+var (
+	configReloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eos_exporter",
+		Name:      "config_last_reload_successful",
+		Help:      "eos_exporter: Whether the last configuration reload attempt was successful.",
+	})
+	configReloadSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eos_exporter",
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "eos_exporter: Timestamp of the last successful configuration reload.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(configReloadSuccess, configReloadSeconds)
+}
+
+var errNothingToExport = errors.New("no EOS instance to export on the metrics path nor instances to probe")
+
+// eosEnvFile holds the name of the local EOS instance, on the MGM.
+const eosEnvFile = "/etc/sysconfig/eos_env"
+
+// state is the configuration in use by the handlers, replaced as a whole on reload.
+type state struct {
+	opts *Options
+	cfg  *Config
+
+	// exporter of the instance served on the metrics path, nil when only probing.
+	exporter *EOSExporter
+
+	// stop ends the background polling of the exporter.
+	stop context.CancelFunc
+}
+
+var (
+	currentMu sync.RWMutex
+	current   *state
+)
+
+// currentState returns the configuration in use.
+func currentState() *state {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// newState builds the exporter from the command line options and the configuration file, if any.
+func newState(base *Options) (*state, error) {
+	s := &state{opts: base, cfg: &Config{}}
+	if base.ConfigFile != "" {
+		cfg, err := loadConfig(base.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		s.cfg, s.opts = cfg, cfg.apply(*base)
+	}
+
+	if s.opts.EOSInstance == "" {
+		if len(s.cfg.Instances) == 0 {
+			return nil, errNothingToExport
+		}
+		return s, nil
+	}
+
+	url := s.opts.EOSMGMURL
+	if url == "" {
+		instance, err := getEOSInstance()
+		if err != nil {
+			return nil, fmt.Errorf("no MGM URL given, and %w", err)
+		}
+		url = "root://" + instance + ".cern.ch"
+	}
+
+	opt := &eosclient.Options{
+		URL:            url,
+		EosBinary:      s.opts.EOSBinary,
+		XrdcopyBinary:  s.opts.XrdcopyBinary,
+		RoleUser:       s.opts.EOSRoleUser,
+		RoleGroup:      s.opts.EOSRoleGroup,
+		Keytab:         s.opts.EOSKeytab,
+		CommandTimeout: s.opts.EOSCommandTimeout,
+	}
+	exporter, err := NewEOSExporter(s.opts.EOSInstance, opt, s.opts)
+	if err != nil {
+		return nil, err
+	}
+	s.exporter = exporter
+	return s, nil
+}
+
+// getEOSInstance returns the name of the EOS instance of the MGM the exporter runs on.
+func getEOSInstance() (string, error) {
+	file, err := os.Open(eosEnvFile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var instance string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		l := scanner.Text()
+		if strings.HasPrefix(l, "EOS_INSTANCE_NAME=") {
+			instance = strings.Trim(strings.TrimPrefix(l, "EOS_INSTANCE_NAME="), "\"")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if instance == "" {
+		return "", fmt.Errorf("no EOS_INSTANCE_NAME in %s", eosEnvFile)
+	}
+	return instance, nil
+}
+
+// start makes s the configuration in use, and stops the previous one.
+func (s *state) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop = cancel
+	if s.exporter != nil {
+		s.exporter.Start(ctx)
+	}
+	if s.exporter != nil && s.opts.PollInterval > 0 {
+		s.exporter.Poll(ctx, s.opts.PollInterval)
+	}
+
+	currentMu.Lock()
+	old := current
+	current = s
+	currentMu.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+}
+
+// reload reads the configuration file again. The configuration in use is kept if the new one is invalid.
+func reload() error {
+	s, err := newState(cmdOptions)
+	if err != nil {
+		configReloadSuccess.Set(0)
+		return err
+	}
+	s.start()
+	configReloadSuccess.Set(1)
+	configReloadSeconds.SetToCurrentTime()
+	return nil
+}
+
+// reloadOnSignal reloads the configuration every time the process gets a SIGHUP.
+func reloadOnSignal() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := reload(); err != nil {
+				log.Errorf("error reloading the configuration: %s", err)
+				continue
+			}
+			log.Infoln("Configuration reloaded")
+		}
+	}()
+}
+
+// reloadHandler reloads the configuration on POST /-/reload.
+func reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := reload(); err != nil {
+		log.Errorf("error reloading the configuration: %s", err)
+		http.Error(w, "failed to reload the configuration: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Infoln("Configuration reloaded")
+}